@@ -0,0 +1,65 @@
+package api
+
+import (
+	"economic_indicator/macro"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// HandleCalendar lists scheduled releases, soonest first. Query parameters:
+// currency (comma-separated codes), from and to (RFC3339 or YYYY-MM-DD,
+// default now to a week ahead) and importance (minimum, 1-3).
+func (a *API) HandleCalendar(w http.ResponseWriter, r *http.Request) {
+	var opts macro.CalendarOptions
+	q := r.URL.Query()
+
+	if v := q.Get("currency"); v != "" {
+		for _, code := range strings.Split(v, ",") {
+			code = strings.ToUpper(strings.TrimSpace(code))
+			if _, ok := macro.CurrencyCountries[code]; !ok {
+				writeError(w, http.StatusBadRequest, "unknown currency "+code)
+				return
+			}
+			opts.Currencies = append(opts.Currencies, code)
+		}
+	}
+	if v := q.Get("from"); v != "" {
+		t, err := parseTime(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid from: "+err.Error())
+			return
+		}
+		opts.From = t
+	}
+	if v := q.Get("to"); v != "" {
+		t, err := parseTime(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid to: "+err.Error())
+			return
+		}
+		opts.To = t
+	}
+	if !opts.From.IsZero() && !opts.To.IsZero() && opts.To.Before(opts.From) {
+		writeError(w, http.StatusBadRequest, "to is before from")
+		return
+	}
+	if v := q.Get("importance"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < macro.ImportanceLow || n > macro.ImportanceHigh {
+			writeError(w, http.StatusBadRequest, "invalid importance "+v+" (want 1-3)")
+			return
+		}
+		opts.MinImportance = n
+	}
+
+	events, err := a.Snapshots.Calendar(r.Context(), opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load calendar: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": events,
+	})
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"database/sql"
+	"economic_indicator/macro"
+	"economic_indicator/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const defaultHistoryWindow = 30 * 24 * time.Hour
+
+type scorePoint struct {
+	TS         time.Time          `json:"ts"`
+	TotalScore float64            `json:"total_score"`
+	Components map[string]float64 `json:"components"`
+}
+
+// HandleCurrencyScoreHistory HandleCurrencyScoreHistory
+func (a *API) HandleCurrencyScoreHistory(w http.ResponseWriter, r *http.Request) {
+	code := strings.ToUpper(chi.URLParam(r, "code"))
+
+	from, to, interval, err := parseHistoryParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	model := historyModel(r)
+
+	var currency models.Currency
+	err = a.DB.NewSelect().
+		Model(&currency).
+		Where("code = ?", code).
+		Scan(r.Context())
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "unknown currency "+code)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return
+	}
+
+	var rows []models.CurrencyScore
+	err = a.DB.NewSelect().
+		Model(&rows).
+		Where("currency_id = ?", currency.ID).
+		Where("model = ?", model).
+		Where("ts >= ?", from).
+		Where("ts <= ?", to).
+		Order("ts ASC").
+		Scan(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return
+	}
+
+	points := make([]scorePoint, 0, len(rows))
+	for _, row := range rows {
+		points = append(points, scorePoint{TS: row.TS, TotalScore: row.EconScore, Components: row.Components})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"code":  code,
+		"model": model,
+		"data":  downsample(points, interval),
+	})
+}
+
+// HandleInstrumentScoreHistory HandleInstrumentScoreHistory
+func (a *API) HandleInstrumentScoreHistory(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.ToUpper(chi.URLParam(r, "symbol"))
+
+	from, to, interval, err := parseHistoryParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	model := historyModel(r)
+
+	var instrument models.Instrument
+	err = a.DB.NewSelect().
+		Model(&instrument).
+		Where("symbol = ?", symbol).
+		Scan(r.Context())
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "unknown instrument "+symbol)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return
+	}
+
+	var rows []models.InstrumentScore
+	err = a.DB.NewSelect().
+		Model(&rows).
+		Where("instrument_id = ?", instrument.ID).
+		Where("model = ?", model).
+		Where("ts >= ?", from).
+		Where("ts <= ?", to).
+		Order("ts ASC").
+		Scan(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return
+	}
+
+	points := make([]scorePoint, 0, len(rows))
+	for _, row := range rows {
+		points = append(points, scorePoint{TS: row.TS, TotalScore: row.FinalScore, Components: row.Components})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"symbol": symbol,
+		"model":  model,
+		"data":   downsample(points, interval),
+	})
+}
+
+// HandleScoreChanges lists the score changes recorded by the scoring engine,
+// newest first, optionally filtered by kind and subject.
+func (a *API) HandleScoreChanges(w http.ResponseWriter, r *http.Request) {
+	from, to, _, err := parseHistoryParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q := r.URL.Query()
+
+	limit := 100
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	var rows []models.ScoreChangeEvent
+	query := a.DB.NewSelect().
+		Model(&rows).
+		Where("model = ?", historyModel(r)).
+		Where("ts >= ?", from).
+		Where("ts <= ?", to).
+		Order("ts DESC", "id DESC").
+		Limit(limit)
+	if v := q.Get("kind"); v != "" {
+		query = query.Where("kind = ?", v)
+	}
+	if v := q.Get("subject"); v != "" {
+		query = query.Where("subject = ?", strings.ToUpper(v))
+	}
+	if err := query.Scan(r.Context()); err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return
+	}
+
+	changes := make([]macro.ScoreChange, 0, len(rows))
+	for _, row := range rows {
+		changes = append(changes, macro.ScoreChange{
+			TS:          row.TS,
+			Kind:        row.Kind,
+			Subject:     row.Subject,
+			Model:       row.Model,
+			Before:      row.Before,
+			After:       row.After,
+			Delta:       row.Delta,
+			TriggeredBy: strings.Split(row.TriggeredBy, ","),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": changes,
+	})
+}
+
+// parseHistoryParams reads from/to/interval; from defaults to 30 days before to,
+// to defaults to now and interval 0 means every stored point.
+func parseHistoryParams(r *http.Request) (from, to time.Time, interval time.Duration, err error) {
+	q := r.URL.Query()
+
+	to = time.Now().UTC()
+	if v := q.Get("to"); v != "" {
+		if to, err = parseTime(v); err != nil {
+			return from, to, 0, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+
+	from = to.Add(-defaultHistoryWindow)
+	if v := q.Get("from"); v != "" {
+		if from, err = parseTime(v); err != nil {
+			return from, to, 0, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+
+	if from.After(to) {
+		return from, to, 0, fmt.Errorf("from must be before to")
+	}
+
+	if v := q.Get("interval"); v != "" {
+		if interval, err = parseInterval(v); err != nil {
+			return from, to, 0, fmt.Errorf("invalid interval: %w", err)
+		}
+	}
+
+	return from, to, interval, nil
+}
+
+// historyModel returns the ?model= filter, defaulting to the default model.
+func historyModel(r *http.Request) string {
+	if m := r.URL.Query().Get("model"); m != "" {
+		return m
+	}
+	return macro.DefaultModelName
+}
+
+// parseTime accepts RFC3339 timestamps or plain dates (YYYY-MM-DD, UTC).
+func parseTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+// parseInterval accepts Go durations plus day ("1d") and week ("1w") suffixes.
+func parseInterval(v string) (time.Duration, error) {
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(v, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(v, "w"):
+		unit = 7 * 24 * time.Hour
+	default:
+		d, err := time.ParseDuration(v)
+		if err == nil && d <= 0 {
+			err = fmt.Errorf("must be positive")
+		}
+		return d, err
+	}
+
+	n, err := strconv.Atoi(v[:len(v)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("expected e.g. 1d or 2w, got %q", v)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// downsample keeps the last point of each interval bucket; points must be sorted by ts.
+func downsample(points []scorePoint, interval time.Duration) []scorePoint {
+	if interval <= 0 || len(points) == 0 {
+		return points
+	}
+
+	out := make([]scorePoint, 0, len(points))
+	for _, p := range points {
+		bucket := p.TS.Truncate(interval)
+		if n := len(out); n > 0 && out[n-1].TS.Truncate(interval).Equal(bucket) {
+			out[n-1] = p
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
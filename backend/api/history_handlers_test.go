@@ -0,0 +1,73 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"1h", time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"1d", 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"0s", 0, true},
+		{"-1h", 0, true},
+		{"0d", 0, true},
+		{"-2w", 0, true},
+		{"1.5d", 0, true},
+		{"d", 0, true},
+		{"daily", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseInterval(tt.in)
+			if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+				t.Fatalf("parseInterval(%q) = %v, %v, want %v (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	day := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	points := func(offsets ...time.Duration) []scorePoint {
+		out := make([]scorePoint, len(offsets))
+		for i, o := range offsets {
+			out[i] = scorePoint{TS: day.Add(o), TotalScore: float64(i)}
+		}
+		return out
+	}
+	all := points(10*time.Minute, 50*time.Minute, 80*time.Minute, 185*time.Minute, 30*time.Hour)
+
+	tests := []struct {
+		name     string
+		points   []scorePoint
+		interval time.Duration
+		want     []float64 // TotalScore of the kept points
+	}{
+		{"no interval keeps everything", all, 0, []float64{0, 1, 2, 3, 4}},
+		{"hourly keeps the last per hour", all, time.Hour, []float64{1, 2, 3, 4}},
+		{"daily keeps the last per UTC day", all, 24 * time.Hour, []float64{3, 4}},
+		{"interval wider than the range", all, 7 * 24 * time.Hour, []float64{4}},
+		{"empty", nil, time.Hour, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := downsample(tt.points, tt.interval)
+			if len(got) != len(tt.want) {
+				t.Fatalf("kept %d points, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, p := range got {
+				if p.TotalScore != tt.want[i] {
+					t.Fatalf("kept %+v, want scores %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -1,24 +1,301 @@
 package api
 
 import (
+	"database/sql"
 	"economic_indicator/macro"
+	"economic_indicator/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
 )
 
+// instrumentPayload is the JSON shape of an instrument in the catalogue endpoints.
+type instrumentPayload struct {
+	Symbol         string                    `json:"symbol"`
+	Name           string                    `json:"name"`
+	AssetType      string                    `json:"asset_type"`
+	DriverCurrency string                    `json:"driver_currency"`
+	QuoteCurrency  string                    `json:"quote_currency,omitempty"` // fx only
+	Drivers        []models.InstrumentDriver `json:"drivers"`
+	CreatedAt      time.Time                 `json:"created_at"`
+}
+
+func instrumentToPayload(m models.Instrument) instrumentPayload {
+	drivers := m.Drivers
+	if drivers == nil {
+		drivers = []models.InstrumentDriver{}
+	}
+	return instrumentPayload{
+		Symbol:         m.Symbol,
+		Name:           m.Name,
+		AssetType:      m.AssetType,
+		DriverCurrency: m.DriverCurrency,
+		QuoteCurrency:  m.QuoteCurrency,
+		Drivers:        drivers,
+		CreatedAt:      m.CreatedAt,
+	}
+}
+
+// normalize upper-cases codes and checks the fields scoring relies on; driver
+// components must be produced by model, the model the scores are built with.
+func (p *instrumentPayload) normalize(model *macro.ScoringModel) error {
+	p.Symbol = strings.ToUpper(strings.TrimSpace(p.Symbol))
+	p.AssetType = strings.ToLower(strings.TrimSpace(p.AssetType))
+	p.DriverCurrency = strings.ToUpper(strings.TrimSpace(p.DriverCurrency))
+	p.QuoteCurrency = strings.ToUpper(strings.TrimSpace(p.QuoteCurrency))
+	for i := range p.Drivers {
+		p.Drivers[i].Currency = strings.ToUpper(strings.TrimSpace(p.Drivers[i].Currency))
+		p.Drivers[i].Component = strings.TrimSpace(p.Drivers[i].Component)
+	}
+
+	if p.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	if !macro.ValidAssetType(p.AssetType) {
+		return fmt.Errorf("asset_type must be one of %s", strings.Join(macro.AssetTypes, ", "))
+	}
+	if p.AssetType == "fx" {
+		// EURUSD → driver EUR, quote USD unless given explicitly
+		if p.DriverCurrency == "" && p.QuoteCurrency == "" && len(p.Symbol) == 6 {
+			p.DriverCurrency, p.QuoteCurrency = p.Symbol[:3], p.Symbol[3:]
+		}
+		if !isCurrencyCode(p.QuoteCurrency) || p.QuoteCurrency == p.DriverCurrency {
+			return fmt.Errorf("fx instruments need a 3-letter quote_currency different from driver_currency")
+		}
+	} else {
+		p.QuoteCurrency = ""
+	}
+	if !isCurrencyCode(p.DriverCurrency) {
+		return fmt.Errorf("driver_currency must be a 3-letter currency code")
+	}
+	components := model.Components()
+	for _, d := range p.Drivers {
+		if !isCurrencyCode(d.Currency) && d.Currency != macro.GlobalDriver {
+			return fmt.Errorf("driver currency must be a 3-letter code or %s, got %q", macro.GlobalDriver, d.Currency)
+		}
+		if d.Component != "" && !slices.Contains(components, d.Component) {
+			return fmt.Errorf("driver %s component must be empty or one of %s, got %q", d.Currency, strings.Join(components, ", "), d.Component)
+		}
+		if d.Weight == 0 || math.IsNaN(d.Weight) || math.Abs(d.Weight) > 5 {
+			return fmt.Errorf("driver %s weight must be non-zero and within ±5", d.Currency)
+		}
+	}
+	return nil
+}
+
+func isCurrencyCode(v string) bool {
+	if len(v) != 3 {
+		return false
+	}
+	for _, c := range v {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
 // HandleInstrumentScores HandleInstrumentScores
 func (a *API) HandleInstrumentScores(w http.ResponseWriter, r *http.Request) {
-	snapshots, err := macro.LoadSnapshots("data/macro.json")
+	opts, err := a.scoreOptions(r)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to load macro data: "+err.Error())
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	if engine := a.liveEngine(); engine != nil {
+		if instScores, ok := engine.InstrumentScores(opts); ok {
+			writeJSON(w, http.StatusOK, map[string]any{
+				"data": instScores,
+			})
+			return
+		}
+	}
+
 	// 1) build currency scores
-	currencyScores := macro.BuildScoresByCountry(snapshots)
+	currencyScores, err := a.Snapshots.Scores(r.Context(), opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load macro data: "+err.Error())
+		return
+	}
+
+	instruments, err := a.Instruments.List(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load instruments: "+err.Error())
+		return
+	}
+
 	// 2) derive instrument scores
-	instScores := macro.BuildInstrumentScores(currencyScores)
+	instScores := macro.BuildInstrumentScores(currencyScores, instruments)
 
 	writeJSON(w, http.StatusOK, map[string]any{
 		"data": instScores,
 	})
 }
+
+// HandleListInstruments HandleListInstruments
+func (a *API) HandleListInstruments(w http.ResponseWriter, r *http.Request) {
+	var rows []models.Instrument
+	err := a.DB.NewSelect().
+		Model(&rows).
+		Order("symbol ASC").
+		Scan(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return
+	}
+
+	out := make([]instrumentPayload, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, instrumentToPayload(row))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"data": out})
+}
+
+// HandleGetInstrument HandleGetInstrument
+func (a *API) HandleGetInstrument(w http.ResponseWriter, r *http.Request) {
+	inst, ok := a.findInstrument(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": instrumentToPayload(inst)})
+}
+
+// HandleCreateInstrument HandleCreateInstrument
+func (a *API) HandleCreateInstrument(w http.ResponseWriter, r *http.Request) {
+	var p instrumentPayload
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	model, err := a.Models.Get(macro.DefaultModelName)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := p.normalize(model); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	exists, err := a.DB.NewSelect().
+		Model((*models.Instrument)(nil)).
+		Where("symbol = ?", p.Symbol).
+		Exists(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return
+	}
+	if exists {
+		writeError(w, http.StatusConflict, "instrument "+p.Symbol+" already exists")
+		return
+	}
+
+	inst := models.Instrument{
+		Symbol:         p.Symbol,
+		Name:           p.Name,
+		AssetType:      p.AssetType,
+		DriverCurrency: p.DriverCurrency,
+		QuoteCurrency:  p.QuoteCurrency,
+		Drivers:        p.Drivers,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if _, err := a.DB.NewInsert().Model(&inst).Exec(r.Context()); err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return
+	}
+	a.catalogueChanged()
+
+	writeJSON(w, http.StatusCreated, map[string]any{"data": instrumentToPayload(inst)})
+}
+
+// HandleUpdateInstrument HandleUpdateInstrument
+func (a *API) HandleUpdateInstrument(w http.ResponseWriter, r *http.Request) {
+	inst, ok := a.findInstrument(w, r)
+	if !ok {
+		return
+	}
+
+	var p instrumentPayload
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	// the symbol is the resource key and cannot be changed here
+	p.Symbol = inst.Symbol
+	model, err := a.Models.Get(macro.DefaultModelName)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := p.normalize(model); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	inst.Name = p.Name
+	inst.AssetType = p.AssetType
+	inst.DriverCurrency = p.DriverCurrency
+	inst.QuoteCurrency = p.QuoteCurrency
+	inst.Drivers = p.Drivers
+
+	if _, err := a.DB.NewUpdate().Model(&inst).WherePK().Exec(r.Context()); err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return
+	}
+	a.catalogueChanged()
+
+	writeJSON(w, http.StatusOK, map[string]any{"data": instrumentToPayload(inst)})
+}
+
+// HandleDeleteInstrument HandleDeleteInstrument
+func (a *API) HandleDeleteInstrument(w http.ResponseWriter, r *http.Request) {
+	inst, ok := a.findInstrument(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := a.DB.NewDelete().Model(&inst).WherePK().Exec(r.Context()); err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return
+	}
+	a.catalogueChanged()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// catalogueChanged has the live engine rebuild its instrument scores, which it
+// would otherwise keep until the next refresh.
+func (a *API) catalogueChanged() {
+	if engine := a.liveEngine(); engine != nil {
+		engine.RescoreAll()
+	}
+}
+
+// findInstrument loads the {symbol} instrument, writing a 404/500 when it can't.
+func (a *API) findInstrument(w http.ResponseWriter, r *http.Request) (models.Instrument, bool) {
+	symbol := strings.ToUpper(chi.URLParam(r, "symbol"))
+
+	var inst models.Instrument
+	err := a.DB.NewSelect().
+		Model(&inst).
+		Where("symbol = ?", symbol).
+		Scan(r.Context())
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "unknown instrument "+symbol)
+		return inst, false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return inst, false
+	}
+	return inst, true
+}
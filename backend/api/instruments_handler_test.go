@@ -0,0 +1,42 @@
+package api
+
+import (
+	"economic_indicator/macro"
+	"economic_indicator/models"
+	"strings"
+	"testing"
+)
+
+func TestInstrumentPayloadNormalize(t *testing.T) {
+	model := macro.DefaultScoringModel()
+	tests := []struct {
+		name    string
+		drivers []models.InstrumentDriver
+		wantErr string
+	}{
+		{name: "total score", drivers: []models.InstrumentDriver{{Currency: "usd", Weight: 1}}},
+		{name: "model component", drivers: []models.InstrumentDriver{{Currency: "GLOBAL", Component: " inflation ", Weight: -0.3}}},
+		{name: "unknown component", drivers: []models.InstrumentDriver{{Currency: "USD", Component: "housing", Weight: 1}}, wantErr: `component must be empty or one of`},
+		{name: "component of another case", drivers: []models.InstrumentDriver{{Currency: "USD", Component: "Inflation", Weight: 1}}, wantErr: `got "Inflation"`},
+		{name: "unknown currency", drivers: []models.InstrumentDriver{{Currency: "US", Weight: 1}}, wantErr: "3-letter code or GLOBAL"},
+		{name: "zero weight", drivers: []models.InstrumentDriver{{Currency: "USD"}}, wantErr: "weight must be non-zero"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := instrumentPayload{Symbol: "xauusd", AssetType: "Metal", DriverCurrency: "usd", Drivers: tt.drivers}
+			err := p.normalize(model)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Symbol != "XAUUSD" || p.AssetType != "metal" || p.Drivers[0].Currency != strings.ToUpper(p.Drivers[0].Currency) || p.Drivers[0].Component != strings.TrimSpace(p.Drivers[0].Component) {
+				t.Fatalf("payload not normalised: %+v", p)
+			}
+		})
+	}
+}
@@ -1,20 +1,28 @@
 package api
 
 import (
+	"context"
 	"economic_indicator/macro"
+	"fmt"
 	"net/http"
+	"strconv"
 )
 
-const macroFilePath = "data/macro.json" // adjust if needed
+const macroFilePath = "data/macro.json" // fallback when econ_indicators is empty
+
 // HandleMacroScores HandleMacroScores
 func (a *API) HandleMacroScores(w http.ResponseWriter, r *http.Request) {
-	snapshots, err := macro.LoadSnapshots(macroFilePath)
+	opts, err := a.scoreOptions(r)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to load macro data: "+err.Error())
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	scoresMap := macro.BuildScoresByCountry(snapshots)
+	scoresMap, err := a.currencyScores(r.Context(), opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load macro data: "+err.Error())
+		return
+	}
 
 	writeJSON(w, http.StatusOK, map[string]any{
 		"data": scoresMap,
@@ -31,13 +39,19 @@ func (a *API) HandleMacroPairSentiment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	snapshots, err := macro.LoadSnapshots(macroFilePath)
+	opts, err := a.scoreOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scores, err := a.currencyScores(r.Context(), opts)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to load macro data: "+err.Error())
 		return
 	}
 
-	pairSentiment, err := macro.PairSentimentFromSnapshots(snapshots, base, quote)
+	pairSentiment, err := macro.PairSentimentFromScores(scores, base, quote)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
@@ -46,4 +60,169 @@ func (a *API) HandleMacroPairSentiment(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, pairSentiment)
 }
 
+// HandleMacroPairMatrix HandleMacroPairMatrix
+func (a *API) HandleMacroPairMatrix(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var minAbs float64
+	if v := q.Get("min_abs"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			writeError(w, http.StatusBadRequest, "min_abs must be a non-negative number")
+			return
+		}
+		minAbs = f
+	}
+
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+
+	opts, err := a.scoreOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scores, err := a.currencyScores(r.Context(), opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load macro data: "+err.Error())
+		return
+	}
+
+	matrix := macro.BuildPairMatrix(scores, minAbs)
+	if limit > 0 && len(matrix.Ranked) > limit {
+		matrix.Ranked = matrix.Ranked[:limit]
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": matrix,
+	})
+}
+
+// HandleScoringModels HandleScoringModels
+func (a *API) HandleScoringModels(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": a.Models.Models(),
+	})
+}
+
+// HandleIndicators lists the canonical indicator registry together with stored
+// categories that do not map onto it.
+func (a *API) HandleIndicators(w http.ResponseWriter, r *http.Request) {
+	unmapped, err := a.Snapshots.UnmappedCategories(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "database error: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": map[string]any{
+			"indicators": macro.Indicators,
+			"unmapped":   unmapped,
+		},
+	})
+}
+
+// HandleMacroSurprise returns the economic surprise index per currency.
+func (a *API) HandleMacroSurprise(w http.ResponseWriter, r *http.Request) {
+	var opts macro.SurpriseOptions
+	q := r.URL.Query()
+
+	if v := q.Get("as_of"); v != "" {
+		t, err := parseTime(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid as_of: "+err.Error())
+			return
+		}
+		opts.AsOf = t
+	}
+	if v := q.Get("half_life"); v != "" {
+		d, err := parseInterval(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid half_life: "+err.Error())
+			return
+		}
+		opts.HalfLife = d
+	}
+	if v := q.Get("window"); v != "" {
+		d, err := parseInterval(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid window: "+err.Error())
+			return
+		}
+		opts.Window = d
+	}
+
+	surprises, err := a.Snapshots.Surprises(r.Context(), opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to compute surprise index: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": surprises,
+	})
+}
+
+// scoreOptions reads the scoring query parameters shared by the macro
+// and instrument endpoints (as_of, model, normalization, min_coverage,
+// decay_half_life).
+func (a *API) scoreOptions(r *http.Request) (macro.ScoreOptions, error) {
+	var opts macro.ScoreOptions
+
+	if v := r.URL.Query().Get("as_of"); v != "" {
+		t, err := parseTime(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid as_of: %w", err)
+		}
+		opts.AsOf = t
+	}
+
+	model, err := a.Models.Get(r.URL.Query().Get("model"))
+	if err != nil {
+		return opts, err
+	}
+	opts.Model = model
+
+	if opts.Normalization, err = macro.ParseNormalization(r.URL.Query().Get("normalization")); err != nil {
+		return opts, err
+	}
+
+	if v := r.URL.Query().Get("min_coverage"); v != "" {
+		c, err := strconv.ParseFloat(v, 64)
+		if err != nil || c < 0 || c > 1 {
+			return opts, fmt.Errorf("invalid min_coverage %q (want 0-1)", v)
+		}
+		opts.MinCoverage = c
+	}
+
+	if v := r.URL.Query().Get("decay_half_life"); v != "" {
+		d, err := parseInterval(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid decay_half_life: %w", err)
+		}
+		opts.DecayHalfLife = d
+	}
+
+	return opts, nil
+}
+
+// currencyScores serves the scoring engine's live scores when it tracks what
+// opts asks for, and scores on demand otherwise.
+func (a *API) currencyScores(ctx context.Context, opts macro.ScoreOptions) (map[string]macro.ScoreBreakdown, error) {
+	if engine := a.liveEngine(); engine != nil {
+		if scores, ok := engine.Scores(opts); ok {
+			return scores, nil
+		}
+	}
+	return a.Snapshots.Scores(ctx, opts)
+}
+
 // reuse your existing writeJSON/writeError helpers from handlers.go
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"economic_indicator/macro"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandlePolicy returns a currency's central-bank decision history and policy stance.
+func (a *API) HandlePolicy(w http.ResponseWriter, r *http.Request) {
+	code := strings.ToUpper(chi.URLParam(r, "code"))
+
+	var opts macro.PolicyOptions
+	q := r.URL.Query()
+
+	if v := q.Get("as_of"); v != "" {
+		t, err := parseTime(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid as_of: "+err.Error())
+			return
+		}
+		opts.AsOf = t
+	}
+	if v := q.Get("window"); v != "" {
+		d, err := parseInterval(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid window: "+err.Error())
+			return
+		}
+		opts.Window = d
+	}
+
+	stances, err := a.Snapshots.PolicyStances(r.Context(), opts, code)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to compute policy stance: "+err.Error())
+		return
+	}
+	stance, ok := stances[code]
+	if !ok {
+		writeError(w, http.StatusNotFound, "no policy decisions for "+code)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": stance,
+	})
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"economic_indicator/macro"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -10,20 +11,40 @@ import (
 
 // API api
 type API struct {
-	DB *bun.DB
+	DB          *bun.DB
+	Snapshots   *macro.SnapshotRepository
+	Instruments *macro.InstrumentRepository
+	Models      *macro.ModelRegistry
+	Engine      *macro.Engine // optional; serves live scores when set
 }
 
 // New new
-func New(db *bun.DB) *API {
-	return &API{DB: db}
+func New(db *bun.DB, models *macro.ModelRegistry) *API {
+	return &API{
+		DB:          db,
+		Snapshots:   macro.NewSnapshotRepository(db, macroFilePath),
+		Instruments: macro.NewInstrumentRepository(db),
+		Models:      models,
+	}
 }
 
+// liveEngine returns the scoring engine while it is running, else nil.
+func (a *API) liveEngine() *macro.Engine {
+	if a.Engine == nil || !a.Engine.Running() {
+		return nil
+	}
+	return a.Engine
+}
+
+// frontendOrigin is the dev frontend allowed by CORS and the WebSocket origin check.
+const frontendOrigin = "http://localhost:5173"
+
 func cors(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// allow your dev frontend
-		w.Header().Set("Access-Control-Allow-Origin", "http://localhost:5173")
+		w.Header().Set("Access-Control-Allow-Origin", frontendOrigin)
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
@@ -47,9 +68,37 @@ func (a *API) Router() http.Handler {
 	r.Get("/api/v1/currencies", a.HandleListCurrencies)
 
 	// new ones:
+	r.Get("/api/v1/macro/models", a.HandleScoringModels)
+	r.Get("/api/v1/macro/indicators", a.HandleIndicators)
 	r.Get("/api/v1/macro/scores", a.HandleMacroScores)
 	r.Get("/api/v1/macro/pair", a.HandleMacroPairSentiment)
+	r.Get("/api/v1/macro/pairs", a.HandleMacroPairMatrix)
+	r.Get("/api/v1/macro/surprise", a.HandleMacroSurprise)
 	r.Get("/api/v1/instruments/scores", a.HandleInstrumentScores)
 
+	// central-bank policy (requires ingest -policy to have run)
+	r.Get("/api/v1/policy/{code}", a.HandlePolicy)
+
+	// economic calendar (requires ingest -calendar to have run)
+	r.Get("/api/v1/calendar", a.HandleCalendar)
+
+	// instrument catalogue
+	r.Get("/api/v1/instruments", a.HandleListInstruments)
+	r.Post("/api/v1/instruments", a.HandleCreateInstrument)
+	r.Get("/api/v1/instruments/{symbol}", a.HandleGetInstrument)
+	r.Put("/api/v1/instruments/{symbol}", a.HandleUpdateInstrument)
+	r.Delete("/api/v1/instruments/{symbol}", a.HandleDeleteInstrument)
+
+	// score history (requires the score job to have run)
+	r.Get("/api/v1/macro/scores/{code}/history", a.HandleCurrencyScoreHistory)
+	r.Get("/api/v1/instruments/{symbol}/history", a.HandleInstrumentScoreHistory)
+
+	// score changes recorded by the scoring engine
+	r.Get("/api/v1/macro/changes", a.HandleScoreChanges)
+
+	// live score and release stream (requires the scoring engine)
+	r.Get("/api/v1/stream", a.HandleStream)
+	r.Get("/api/v1/stream/ws", a.HandleStreamWS)
+
 	return r
 }
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"economic_indicator/macro"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	streamHeartbeat = 15 * time.Second // SSE keep-alive comment and WebSocket ping interval
+	streamRetry     = 5 * time.Second  // SSE reconnect delay suggested to clients
+)
+
+// streamTypes lists the event types a stream can be filtered on.
+var streamTypes = []string{macro.ChangeCurrency, macro.ChangePair, macro.ChangeInstrument, macro.EventRelease}
+
+// streamFilter selects the engine events a stream client receives. An event
+// passes when its type is allowed and it concerns one of Currencies or is for
+// one of Symbols; empty lists allow everything.
+type streamFilter struct {
+	Currencies []string `json:"currencies"`
+	Symbols    []string `json:"symbols"`
+	Types      []string `json:"types"`
+}
+
+// parseStreamFilter reads comma-separated currency codes, symbols and event types.
+// A pair symbol matches in either order, since pair events are keyed alphabetically.
+func parseStreamFilter(currency, symbol, types string) (streamFilter, error) {
+	f := streamFilter{Currencies: []string{}, Symbols: []string{}, Types: []string{}}
+	for _, code := range splitList(currency) {
+		code = strings.ToUpper(code)
+		if _, ok := macro.CurrencyCountries[code]; !ok {
+			return f, fmt.Errorf("unknown currency %s", code)
+		}
+		f.Currencies = append(f.Currencies, code)
+	}
+	for _, sym := range splitList(symbol) {
+		sym = strings.ToUpper(sym)
+		f.Symbols = append(f.Symbols, sym)
+		if len(sym) != 6 {
+			continue
+		}
+		_, okBase := macro.CurrencyCountries[sym[:3]]
+		_, okQuote := macro.CurrencyCountries[sym[3:]]
+		if okBase && okQuote {
+			f.Symbols = append(f.Symbols, sym[3:]+sym[:3])
+		}
+	}
+	for _, t := range splitList(types) {
+		t = strings.ToLower(t)
+		if !slices.Contains(streamTypes, t) {
+			return f, fmt.Errorf("unknown event type %s (want one of %s)", t, strings.Join(streamTypes, ", "))
+		}
+		f.Types = append(f.Types, t)
+	}
+	return f, nil
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func (f streamFilter) match(ev macro.EngineEvent) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, ev.Type) {
+		return false
+	}
+	if len(f.Currencies) == 0 && len(f.Symbols) == 0 {
+		return true
+	}
+	if ev.Symbol != "" && slices.Contains(f.Symbols, ev.Symbol) {
+		return true
+	}
+	for _, code := range ev.Currencies {
+		if slices.Contains(f.Currencies, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// streamFilterParams parses the currency, symbol and type query parameters.
+func streamFilterParams(r *http.Request) (streamFilter, error) {
+	q := r.URL.Query()
+	return parseStreamFilter(q.Get("currency"), q.Get("symbol"), q.Get("type"))
+}
+
+// HandleStream pushes score changes and indicator releases as Server-Sent
+// Events, named by event type (currency, pair, instrument or release) with the
+// macro.EngineEvent as JSON data. Query parameters: currency and symbol
+// (comma-separated, an event matching either passes) and type (comma-separated
+// event types). The first event, "subscribed", echoes the filter.
+func (a *API) HandleStream(w http.ResponseWriter, r *http.Request) {
+	engine := a.liveEngine()
+	if engine == nil {
+		writeError(w, http.StatusServiceUnavailable, "scoring engine is not running")
+		return
+	}
+	filter, err := streamFilterParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	events, unsubscribe := engine.Subscribe(0)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // stop nginx buffering the stream
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	send := func(event string, v any) error {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	if err := send("subscribed", filter); err != nil {
+		log.Printf("stream: %v", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if !filter.match(ev) {
+				continue
+			}
+			if err := send(ev.Type, ev); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// streamSubscription is the message a WebSocket client sends to replace its
+// filter; fields take the same comma-separated lists as the query parameters.
+type streamSubscription struct {
+	Currency string `json:"currency"`
+	Symbol   string `json:"symbol"`
+	Type     string `json:"type"`
+}
+
+// HandleStreamWS is HandleStream over a WebSocket. Each event is sent as a
+// text message holding the macro.EngineEvent. The client may send a
+// {"currency", "symbol", "type"} message at any time to replace its filter;
+// the server answers {"type": "subscribed", "filter": ...}, or
+// {"type": "error", "error": ...} for an invalid message.
+func (a *API) HandleStreamWS(w http.ResponseWriter, r *http.Request) {
+	engine := a.liveEngine()
+	if engine == nil {
+		writeError(w, http.StatusServiceUnavailable, "scoring engine is not running")
+		return
+	}
+	filter, err := streamFilterParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if msg := wsHandshakeError(r); msg != "" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if !wsOriginAllowed(r) {
+		writeError(w, http.StatusForbidden, "origin not allowed")
+		return
+	}
+
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		log.Printf("stream: websocket upgrade: %v", err)
+		return
+	}
+	defer conn.conn.Close()
+
+	events, unsubscribe := engine.Subscribe(0)
+	defer unsubscribe()
+
+	// the reader hands filter updates to the writer loop below, which owns the filter
+	filters := make(chan streamFilter)
+	done := make(chan struct{}) // closed when the reader stops
+	quit := make(chan struct{}) // closed when the writer loop returns
+	defer close(quit)
+	go func() {
+		defer close(done)
+		for {
+			_, msg, err := conn.readMessage(2 * streamHeartbeat)
+			switch {
+			case errors.Is(err, errWSTooBig):
+				conn.close(wsCloseTooBig, "message too big")
+				return
+			case errors.Is(err, errWSProtocol):
+				conn.close(wsCloseProtocolError, "protocol error")
+				return
+			case err != nil:
+				return
+			}
+
+			var sub streamSubscription
+			if err := json.Unmarshal(msg, &sub); err != nil {
+				_ = conn.writeJSON(map[string]any{"type": "error", "error": "invalid subscription: " + err.Error()})
+				continue
+			}
+			f, err := parseStreamFilter(sub.Currency, sub.Symbol, sub.Type)
+			if err != nil {
+				_ = conn.writeJSON(map[string]any{"type": "error", "error": err.Error()})
+				continue
+			}
+			select {
+			case filters <- f:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	if err := conn.writeJSON(map[string]any{"type": "subscribed", "filter": filter}); err != nil {
+		return
+	}
+
+	ping := time.NewTicker(streamHeartbeat)
+	defer ping.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case f := <-filters:
+			filter = f
+			if err := conn.writeJSON(map[string]any{"type": "subscribed", "filter": filter}); err != nil {
+				return
+			}
+		case ev, ok := <-events:
+			if !ok {
+				conn.close(wsCloseNormal, "")
+				return
+			}
+			if !filter.match(ev) {
+				continue
+			}
+			if err := conn.writeJSON(ev); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.writeFrame(wsOpPing, nil); err != nil {
+				return
+			}
+		}
+	}
+}
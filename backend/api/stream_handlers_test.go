@@ -0,0 +1,120 @@
+package api
+
+import (
+	"economic_indicator/macro"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseStreamFilter(t *testing.T) {
+	tests := []struct {
+		name                      string
+		currency, symbol, types   string
+		wantCurrencies, wantTypes []string
+		wantSymbols               []string
+		wantErr                   string
+	}{
+		{name: "empty", wantCurrencies: []string{}, wantSymbols: []string{}, wantTypes: []string{}},
+		{
+			name:     "normalised lists",
+			currency: " usd, eur ,", symbol: "us500", types: "Currency,release",
+			wantCurrencies: []string{"USD", "EUR"}, wantSymbols: []string{"US500"}, wantTypes: []string{"currency", "release"},
+		},
+		{
+			name:           "pair symbol in both orders",
+			symbol:         "usdjpy",
+			wantCurrencies: []string{}, wantSymbols: []string{"USDJPY", "JPYUSD"}, wantTypes: []string{},
+		},
+		{
+			name:           "six letters that are not a pair",
+			symbol:         "ABCDEF",
+			wantCurrencies: []string{}, wantSymbols: []string{"ABCDEF"}, wantTypes: []string{},
+		},
+		{name: "unknown currency", currency: "USD,XXX", wantErr: "unknown currency XXX"},
+		{name: "unknown type", types: "trade", wantErr: "unknown event type trade"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseStreamFilter(tt.currency, tt.symbol, tt.types)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(f.Currencies, tt.wantCurrencies) || !slices.Equal(f.Symbols, tt.wantSymbols) || !slices.Equal(f.Types, tt.wantTypes) {
+				t.Fatalf("filter = %+v, want currencies %v symbols %v types %v", f, tt.wantCurrencies, tt.wantSymbols, tt.wantTypes)
+			}
+		})
+	}
+}
+
+func TestStreamFilterMatch(t *testing.T) {
+	usdScore := macro.EngineEvent{Type: macro.ChangeCurrency, Currencies: []string{"USD"}}
+	usdRelease := macro.EngineEvent{Type: macro.EventRelease, Currencies: []string{"USD"}}
+	pair := macro.EngineEvent{Type: macro.ChangePair, Currencies: []string{"JPY", "USD"}, Symbol: "JPYUSD"}
+	index := macro.EngineEvent{Type: macro.ChangeInstrument, Currencies: []string{"EUR"}, Symbol: "GER40"}
+
+	tests := []struct {
+		name                    string
+		currency, symbol, types string
+		ev                      macro.EngineEvent
+		want                    bool
+	}{
+		{"no filter", "", "", "", index, true},
+		{"currency", "USD", "", "", usdScore, true},
+		{"other currency", "EUR", "", "", usdScore, false},
+		{"any currency of a pair", "JPY", "", "", pair, true},
+		{"instrument by its currency", "EUR", "", "", index, true},
+		{"symbol", "", "GER40", "", index, true},
+		{"other symbol", "", "US500", "", index, false},
+		{"symbol filter skips currency events", "", "GER40", "", usdScore, false},
+		{"pair symbol in the other order", "", "USDJPY", "", pair, true},
+		{"currency or symbol", "USD", "GER40", "", index, true},
+		{"type only", "", "", "release", usdRelease, true},
+		{"type excluded", "", "", "release", usdScore, false},
+		{"type and currency", "EUR", "", "currency,release", usdRelease, false},
+		{"type excludes a matching symbol", "", "GER40", "pair", index, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseStreamFilter(tt.currency, tt.symbol, tt.types)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.match(tt.ev); got != tt.want {
+				t.Fatalf("match(%+v) with %+v = %v, want %v", tt.ev, f, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamWithoutRunningEngine(t *testing.T) {
+	stopped := macro.NewEngine(macro.NewSnapshotRepository(nil, "../data/macro.json"), macro.NewInstrumentRepository(nil), macro.ScoreOptions{})
+	for _, tt := range []struct {
+		name   string
+		engine *macro.Engine
+	}{
+		{"no engine", nil},
+		{"engine not started", stopped},
+	} {
+		for path, handler := range map[string]func(*API) http.HandlerFunc{
+			"/api/v1/stream":    func(a *API) http.HandlerFunc { return a.HandleStream },
+			"/api/v1/stream/ws": func(a *API) http.HandlerFunc { return a.HandleStreamWS },
+		} {
+			t.Run(tt.name+" "+path, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				handler(&API{Engine: tt.engine})(rec, httptest.NewRequest(http.MethodGet, path, nil))
+				if rec.Code != http.StatusServiceUnavailable {
+					t.Fatalf("status = %d, want 503", rec.Code)
+				}
+			})
+		}
+	}
+}
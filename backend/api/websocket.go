@@ -0,0 +1,257 @@
+package api
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Minimal server side of RFC 6455: the handshake, unfragmented text frames
+// out, and masked client frames in, with pings and closes answered.
+
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes.
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// WebSocket close codes.
+const (
+	wsCloseNormal        = 1000
+	wsCloseProtocolError = 1002
+	wsCloseTooBig        = 1009
+)
+
+const (
+	wsMaxMessage   = 64 << 10 // clients only send subscription messages
+	wsWriteTimeout = 10 * time.Second
+)
+
+var (
+	errWSProtocol = errors.New("websocket protocol error")
+	errWSTooBig   = errors.New("websocket message too big")
+)
+
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+	mu   sync.Mutex // serialises writes
+}
+
+// wsHandshakeError says why r is not a valid WebSocket upgrade, or "" if it is.
+func wsHandshakeError(r *http.Request) string {
+	switch {
+	case r.Method != http.MethodGet:
+		return "websocket upgrade must be a GET"
+	case !headerHasToken(r.Header, "Connection", "upgrade"), !headerHasToken(r.Header, "Upgrade", "websocket"):
+		return "expected a websocket upgrade request"
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		return "unsupported websocket version (want 13)"
+	case r.Header.Get("Sec-WebSocket-Key") == "":
+		return "missing Sec-WebSocket-Key"
+	}
+	return ""
+}
+
+// wsOriginAllowed accepts requests without an Origin (non-browser clients),
+// from the dev frontend and from the API's own host.
+func wsOriginAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || origin == frontendOrigin {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// upgradeWebSocket takes over the connection of a request that passed
+// wsHandshakeError and completes the handshake.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	conn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijack: %w", err)
+	}
+
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsAcceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n"
+	_ = conn.SetDeadline(time.Time{}) // drop any server read/write timeouts
+	_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if _, err := io.WriteString(conn, resp); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("write handshake: %w", err)
+	}
+	return &wsConn{conn: conn, br: brw.Reader}, nil
+}
+
+// wsAcceptKey is the Sec-WebSocket-Accept value answering a client's key.
+func wsAcceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// writeFrame sends one unmasked, final frame.
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	frame := make([]byte, 0, len(payload)+10)
+	frame = append(frame, 0x80|op)
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	frame = append(frame, payload...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// writeJSON sends v as a text message.
+func (c *wsConn) writeJSON(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(wsOpText, b)
+}
+
+// close sends a close frame, best effort, and closes the connection.
+func (c *wsConn) close(code uint16, reason string) {
+	payload := binary.BigEndian.AppendUint16(nil, code)
+	_ = c.writeFrame(wsOpClose, append(payload, reason...))
+	c.conn.Close()
+}
+
+// readMessage returns the next text or binary message, reassembling fragments
+// and answering pings on the way. A close from the client is echoed and
+// reported as io.EOF. Each frame must arrive within timeout.
+func (c *wsConn) readMessage(timeout time.Duration) (op byte, msg []byte, err error) {
+	started := false
+	for {
+		_ = c.conn.SetReadDeadline(time.Now().Add(timeout))
+		fin, frameOp, data, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch frameOp {
+		case wsOpClose:
+			code := data
+			if len(code) > 2 {
+				code = code[:2]
+			}
+			_ = c.writeFrame(wsOpClose, code)
+			return 0, nil, io.EOF
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, data); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpContinuation:
+			if !started {
+				return 0, nil, errWSProtocol
+			}
+		case wsOpText, wsOpBinary:
+			if started {
+				return 0, nil, errWSProtocol
+			}
+			started, op = true, frameOp
+		default:
+			return 0, nil, errWSProtocol
+		}
+
+		if len(msg)+len(data) > wsMaxMessage {
+			return 0, nil, errWSTooBig
+		}
+		msg = append(msg, data...)
+		if fin {
+			return op, msg, nil
+		}
+	}
+}
+
+// readFrame reads one client frame and unmasks its payload.
+func (c *wsConn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = head[0]&0x80 != 0
+	op = head[0] & 0x0F
+	if head[0]&0x70 != 0 || head[1]&0x80 == 0 { // reserved bits set, or unmasked client frame
+		return false, 0, nil, errWSProtocol
+	}
+
+	n := uint64(head[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if op >= wsOpClose && (n > 125 || !fin) {
+		return false, 0, nil, errWSProtocol
+	}
+	if n > wsMaxMessage {
+		return false, 0, nil, errWSTooBig
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
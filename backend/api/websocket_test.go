@@ -0,0 +1,290 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clientFrame encodes a frame as a client sends it: masked, with the
+// shortest length encoding.
+func clientFrame(fin bool, op byte, payload []byte) []byte {
+	b0 := op
+	if fin {
+		b0 |= 0x80
+	}
+	frame := []byte{b0}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	mask := []byte{0x12, 0x34, 0x56, 0x78}
+	frame = append(frame, mask...)
+	for i, c := range payload {
+		frame = append(frame, c^mask[i%4])
+	}
+	return frame
+}
+
+type serverFrame struct {
+	fin     bool
+	op      byte
+	payload []byte
+}
+
+// parseServerFrames decodes the unmasked frames the server wrote.
+func parseServerFrames(t *testing.T, b []byte) []serverFrame {
+	t.Helper()
+	var out []serverFrame
+	for len(b) > 0 {
+		if len(b) < 2 || b[1]&0x80 != 0 {
+			t.Fatalf("bad server frame header % x", b)
+		}
+		f := serverFrame{fin: b[0]&0x80 != 0, op: b[0] & 0x0F}
+		n := uint64(b[1] & 0x7F)
+		b = b[2:]
+		switch n {
+		case 126:
+			n, b = uint64(binary.BigEndian.Uint16(b)), b[2:]
+		case 127:
+			n, b = binary.BigEndian.Uint64(b), b[8:]
+		}
+		f.payload, b = b[:n], b[n:]
+		out = append(out, f)
+	}
+	return out
+}
+
+// pipeConn returns a wsConn reading input and a function that closes it and
+// returns everything the server wrote.
+func pipeConn(t *testing.T, input []byte) (*wsConn, func() []byte) {
+	t.Helper()
+	server, client := net.Pipe()
+	written := make(chan []byte, 1)
+	go func() {
+		b, _ := io.ReadAll(client)
+		written <- b
+	}()
+	c := &wsConn{conn: server, br: bufio.NewReader(bytes.NewReader(input))}
+	return c, func() []byte {
+		server.Close()
+		return <-written
+	}
+}
+
+func TestWSAcceptKey(t *testing.T) {
+	// the example from RFC 6455 section 1.3
+	if got := wsAcceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("wsAcceptKey = %s", got)
+	}
+}
+
+func TestWSHandshakeError(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    string
+	}{
+		{"valid", "GET", map[string]string{"Connection": "keep-alive, Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "k"}, ""},
+		{"post", "POST", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "k"}, "GET"},
+		{"no upgrade", "GET", map[string]string{"Connection": "keep-alive", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "k"}, "upgrade request"},
+		{"old version", "GET", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "8", "Sec-WebSocket-Key": "k"}, "version"},
+		{"no key", "GET", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13"}, "Sec-WebSocket-Key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/api/v1/ws", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			got := wsHandshakeError(r)
+			if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+				t.Fatalf("wsHandshakeError = %q, want it to mention %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWSReadMessage(t *testing.T) {
+	big := bytes.Repeat([]byte("a"), wsMaxMessage)
+	half := bytes.Repeat([]byte("b"), wsMaxMessage/2+1)
+	join := func(frames ...[]byte) []byte { return bytes.Join(frames, nil) }
+
+	tests := []struct {
+		name    string
+		input   []byte
+		wantOp  byte
+		wantMsg []byte
+		wantErr error
+		replies []serverFrame // frames the server must have written
+	}{
+		{
+			name:    "masked text",
+			input:   clientFrame(true, wsOpText, []byte(`{"currencies":["USD"]}`)),
+			wantOp:  wsOpText,
+			wantMsg: []byte(`{"currencies":["USD"]}`),
+		},
+		{
+			name:    "16-bit length",
+			input:   clientFrame(true, wsOpBinary, bytes.Repeat([]byte{7}, 300)),
+			wantOp:  wsOpBinary,
+			wantMsg: bytes.Repeat([]byte{7}, 300),
+		},
+		{
+			name:    "64-bit length at the limit",
+			input:   clientFrame(true, wsOpText, big),
+			wantOp:  wsOpText,
+			wantMsg: big,
+		},
+		{
+			name:    "frame over the limit",
+			input:   clientFrame(true, wsOpText, append(big, 'a')),
+			wantErr: errWSTooBig,
+		},
+		{
+			name: "fragments reassembled around a ping",
+			input: join(
+				clientFrame(false, wsOpText, []byte("hel")),
+				clientFrame(true, wsOpPing, []byte("p")),
+				clientFrame(false, wsOpContinuation, []byte("l")),
+				clientFrame(true, wsOpContinuation, []byte("o")),
+			),
+			wantOp:  wsOpText,
+			wantMsg: []byte("hello"),
+			replies: []serverFrame{{fin: true, op: wsOpPong, payload: []byte("p")}},
+		},
+		{
+			name:    "fragments over the limit",
+			input:   join(clientFrame(false, wsOpText, half), clientFrame(true, wsOpContinuation, half)),
+			wantErr: errWSTooBig,
+		},
+		{
+			name:    "pong ignored",
+			input:   join(clientFrame(true, wsOpPong, nil), clientFrame(true, wsOpText, []byte("x"))),
+			wantOp:  wsOpText,
+			wantMsg: []byte("x"),
+		},
+		{
+			name:    "continuation without a start",
+			input:   clientFrame(true, wsOpContinuation, []byte("x")),
+			wantErr: errWSProtocol,
+		},
+		{
+			name:    "new message inside a fragmented one",
+			input:   join(clientFrame(false, wsOpText, []byte("a")), clientFrame(true, wsOpText, []byte("b"))),
+			wantErr: errWSProtocol,
+		},
+		{
+			name:    "unmasked client frame",
+			input:   []byte{0x80 | wsOpText, 1, 'x'},
+			wantErr: errWSProtocol,
+		},
+		{
+			name:    "reserved bit set",
+			input:   append([]byte{0xC0 | wsOpText}, clientFrame(true, wsOpText, []byte("x"))[1:]...),
+			wantErr: errWSProtocol,
+		},
+		{
+			name:    "fragmented control frame",
+			input:   clientFrame(false, wsOpPing, []byte("p")),
+			wantErr: errWSProtocol,
+		},
+		{
+			name:    "control frame over 125 bytes",
+			input:   clientFrame(true, wsOpPing, bytes.Repeat([]byte("p"), 126)),
+			wantErr: errWSProtocol,
+		},
+		{
+			name:    "unknown opcode",
+			input:   clientFrame(true, 0x3, []byte("x")),
+			wantErr: errWSProtocol,
+		},
+		{
+			name:    "close echoed with its code",
+			input:   clientFrame(true, wsOpClose, append(binary.BigEndian.AppendUint16(nil, wsCloseNormal), "bye"...)),
+			wantErr: io.EOF,
+			replies: []serverFrame{{fin: true, op: wsOpClose, payload: binary.BigEndian.AppendUint16(nil, wsCloseNormal)}},
+		},
+		{
+			name:    "truncated frame",
+			input:   clientFrame(true, wsOpText, []byte("hello"))[:8],
+			wantErr: io.ErrUnexpectedEOF,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, written := pipeConn(t, tt.input)
+			op, msg, err := c.readMessage(time.Second)
+			replies := parseServerFrames(t, written())
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if op != tt.wantOp || !bytes.Equal(msg, tt.wantMsg) {
+				t.Errorf("message = %d %.40q, want %d %.40q", op, msg, tt.wantOp, tt.wantMsg)
+			}
+			if len(replies) != len(tt.replies) {
+				t.Fatalf("server wrote %d frames, want %d", len(replies), len(tt.replies))
+			}
+			for i, r := range replies {
+				want := tt.replies[i]
+				if r.fin != want.fin || r.op != want.op || !bytes.Equal(r.payload, want.payload) {
+					t.Errorf("reply %d = %+v, want %+v", i, r, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWSWriteFrame(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		header []byte
+	}{
+		{"7-bit length", 125, []byte{0x81, 125}},
+		{"16-bit length", 126, []byte{0x81, 126, 0, 126}},
+		{"16-bit length max", 0xFFFF, []byte{0x81, 126, 0xFF, 0xFF}},
+		{"64-bit length", 0x10000, []byte{0x81, 127, 0, 0, 0, 0, 0, 1, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := bytes.Repeat([]byte("z"), tt.size)
+			c, written := pipeConn(t, nil)
+			if err := c.writeFrame(wsOpText, payload); err != nil {
+				t.Fatal(err)
+			}
+			got := written()
+			if !bytes.Equal(got[:len(tt.header)], tt.header) {
+				t.Errorf("header = % x, want % x", got[:len(tt.header)], tt.header)
+			}
+			if !bytes.Equal(got[len(tt.header):], payload) {
+				t.Errorf("payload is not written unmasked after the header")
+			}
+		})
+	}
+}
+
+func TestWSClose(t *testing.T) {
+	c, written := pipeConn(t, nil)
+	c.close(wsCloseTooBig, "too big")
+	frames := parseServerFrames(t, written())
+	want := append(binary.BigEndian.AppendUint16(nil, wsCloseTooBig), "too big"...)
+	if len(frames) != 1 || frames[0].op != wsOpClose || !bytes.Equal(frames[0].payload, want) {
+		t.Fatalf("close wrote %+v, want one close frame with %q", frames, want)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"economic_indicator/config"
+	"economic_indicator/db"
+	"economic_indicator/macro"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func main() {
+	pricesPath := flag.String("prices", "", "CSV of daily closes with date,symbol,close columns (required)")
+	source := flag.String("source", "stored", "score source: stored (score history tables) or pit (recomputed as of each date)")
+	releaseTimeOnly := flag.Bool("release-time-only", false, "pit source: ignore ingested_at (for backfilled history)")
+	modelName := flag.String("model", macro.DefaultModelName, "scoring model name")
+	symbolsFlag := flag.String("symbols", "", "comma-separated symbols to test (default all in the CSV)")
+	flag.Parse()
+
+	if *pricesPath == "" {
+		log.Fatal("-prices is required")
+	}
+
+	prices, err := loadPrices(*pricesPath)
+	if err != nil {
+		log.Fatalf("load prices: %v", err)
+	}
+
+	cfg := config.Load()
+
+	scoringModels, err := macro.LoadScoringModels(cfg.ScoringModelsPath)
+	if err != nil {
+		log.Fatalf("scoring models: %v", err)
+	}
+	model, err := scoringModels.Get(*modelName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bunDB := db.Open(cfg.DBDSN)
+	ctx := context.Background()
+
+	var scores scoreSource
+	switch *source {
+	case "stored":
+		stored, err := loadStoredSource(ctx, bunDB, model.Name)
+		if err != nil {
+			log.Fatalf("load stored scores: %v", err)
+		}
+		scores = stored
+	case "pit":
+		instruments, err := macro.NewInstrumentRepository(bunDB).List(ctx)
+		if err != nil {
+			log.Fatalf("load instruments: %v", err)
+		}
+		// no JSON fallback: it has no dates and would leak today's data
+		scores = newPITSource(macro.NewSnapshotRepository(bunDB, ""), instruments, model, *releaseTimeOnly)
+	default:
+		log.Fatalf("unknown -source %q (want stored or pit)", *source)
+	}
+
+	symbols := selectSymbols(prices, *symbolsFlag)
+
+	var results []symbolResult
+	for _, symbol := range symbols {
+		obs, err := observations(ctx, scores, symbol, prices[symbol])
+		if err != nil {
+			log.Fatalf("%s: %v", symbol, err)
+		}
+		if len(obs) == 0 {
+			log.Printf("no scores overlap the price history for %s, skipping", symbol)
+			continue
+		}
+		results = append(results, evaluate(symbol, obs))
+	}
+
+	printResults(os.Stdout, results)
+}
+
+func selectSymbols(prices map[string][]pricePoint, filter string) []string {
+	var symbols []string
+	if filter != "" {
+		for _, s := range strings.Split(filter, ",") {
+			s = strings.ToUpper(strings.TrimSpace(s))
+			if _, ok := prices[s]; ok {
+				symbols = append(symbols, s)
+			} else {
+				log.Printf("no prices for %s, skipping", s)
+			}
+		}
+		return symbols
+	}
+
+	for s := range prices {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// observations pairs the score known at each close with the return to the next close.
+func observations(ctx context.Context, scores scoreSource, symbol string, series []pricePoint) ([]observation, error) {
+	var out []observation
+	for i := 0; i+1 < len(series); i++ {
+		// the score must be known by the end of the day whose close we trade at
+		at := series[i].Date.Add(24*time.Hour - time.Second)
+		score, ok, err := scores.ScoreAt(ctx, symbol, at)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || series[i].Close == 0 {
+			continue
+		}
+		out = append(out, observation{
+			Score:      score,
+			NextReturn: series[i+1].Close/series[i].Close - 1,
+		})
+	}
+	return out, nil
+}
+
+func printResults(w io.Writer, results []symbolResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "symbol\tdays\ttrades\thit rate\ttotal ret\tavg daily\tmax dd\tIC\t")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f%%\t%.2f%%\t%.3f%%\t%.2f%%\t%s\t\n",
+			r.Symbol, r.Days, r.Trades,
+			r.HitRate*100, r.TotalReturn*100, r.AvgReturn*100, r.MaxDrawdown*100,
+			formatIC(r.IC))
+	}
+	tw.Flush()
+}
+
+func formatIC(ic float64) string {
+	if math.IsNaN(ic) {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.3f", ic)
+}
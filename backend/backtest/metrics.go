@@ -0,0 +1,138 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// observation pairs the score known at a close with the return to the next close.
+type observation struct {
+	Score      float64
+	NextReturn float64
+}
+
+// symbolResult summarises a long/short-by-score-sign strategy for one symbol.
+type symbolResult struct {
+	Symbol      string
+	Days        int     // observations with a score
+	Trades      int     // days with a non-zero position
+	HitRate     float64 // share of trades whose sign matched the next return
+	TotalReturn float64 // compounded strategy return
+	AvgReturn   float64 // mean daily strategy return
+	MaxDrawdown float64 // largest peak-to-trough equity loss, as a positive fraction
+	IC          float64 // Spearman rank correlation of score vs next return
+}
+
+func evaluate(symbol string, obs []observation) symbolResult {
+	res := symbolResult{Symbol: symbol, Days: len(obs)}
+	if len(obs) == 0 {
+		return res
+	}
+
+	equity, peak := 1.0, 1.0
+	var hits int
+	var sumRet float64
+
+	for _, o := range obs {
+		position := sign(o.Score)
+		ret := position * o.NextReturn
+
+		if position != 0 {
+			res.Trades++
+			if ret > 0 {
+				hits++
+			}
+		}
+
+		sumRet += ret
+		equity *= 1 + ret
+		if equity > peak {
+			peak = equity
+		}
+		if dd := (peak - equity) / peak; dd > res.MaxDrawdown {
+			res.MaxDrawdown = dd
+		}
+	}
+
+	if res.Trades > 0 {
+		res.HitRate = float64(hits) / float64(res.Trades)
+	}
+	res.TotalReturn = equity - 1
+	res.AvgReturn = sumRet / float64(len(obs))
+
+	scores := make([]float64, len(obs))
+	returns := make([]float64, len(obs))
+	for i, o := range obs {
+		scores[i] = o.Score
+		returns[i] = o.NextReturn
+	}
+	res.IC = spearman(scores, returns)
+
+	return res
+}
+
+func sign(v float64) float64 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// spearman is the Pearson correlation of the ranks; NaN when either side is constant.
+func spearman(x, y []float64) float64 {
+	return pearson(ranks(x), ranks(y))
+}
+
+// ranks assigns 1-based ranks, averaging ties.
+func ranks(v []float64) []float64 {
+	idx := make([]int, len(v))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return v[idx[a]] < v[idx[b]] })
+
+	out := make([]float64, len(v))
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && v[idx[j+1]] == v[idx[i]] {
+			j++
+		}
+		avg := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			out[idx[k]] = avg
+		}
+		i = j + 1
+	}
+	return out
+}
+
+func pearson(x, y []float64) float64 {
+	n := float64(len(x))
+	if n < 2 {
+		return math.NaN()
+	}
+
+	var mx, my float64
+	for i := range x {
+		mx += x[i]
+		my += y[i]
+	}
+	mx /= n
+	my /= n
+
+	var cov, vx, vy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(vx*vy)
+}
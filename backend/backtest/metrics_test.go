@@ -0,0 +1,135 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+const eps = 1e-9
+
+func TestRanks(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []float64
+		want []float64
+	}{
+		{"empty", nil, []float64{}},
+		{"distinct", []float64{3, 1, 2}, []float64{3, 1, 2}},
+		{"ties averaged", []float64{1, 2, 2, 3}, []float64{1, 2.5, 2.5, 4}},
+		{"all equal", []float64{5, 5, 5}, []float64{2, 2, 2}},
+		{"negative and unsorted", []float64{0, -1, 1, -1}, []float64{3, 1.5, 4, 1.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ranks(tt.in)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ranks(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("ranks(%v) = %v, want %v", tt.in, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSpearman(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64 // NaN for undefined
+	}{
+		{"monotonic, not linear", []float64{1, 2, 3, 4}, []float64{1, 8, 27, 64}, 1},
+		{"reversed", []float64{1, 2, 3, 4}, []float64{0.4, 0.3, 0.2, 0.1}, -1},
+		{"with ties", []float64{1, -1, 0, 1}, []float64{0.1, 0.05, 0.2, -0.1}, -1 / math.Sqrt(22.5)},
+		{"constant side", []float64{1, 1, 1}, []float64{1, 2, 3}, math.NaN()},
+		{"single point", []float64{1}, []float64{2}, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := spearman(tt.x, tt.y)
+			if math.IsNaN(tt.want) {
+				if !math.IsNaN(got) {
+					t.Fatalf("spearman = %v, want NaN", got)
+				}
+				return
+			}
+			if math.Abs(got-tt.want) > eps {
+				t.Fatalf("spearman = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name string
+		obs  []observation
+		want symbolResult
+	}{
+		{
+			name: "no observations",
+			want: symbolResult{Symbol: "X"},
+		},
+		{
+			// positions +1, -1, 0, +1 earn 0.1, -0.05, 0, -0.1:
+			// equity 1.1, 1.045, 1.045, 0.9405 with the peak at 1.1
+			name: "mixed",
+			obs: []observation{
+				{Score: 0.4, NextReturn: 0.1},
+				{Score: -0.2, NextReturn: 0.05},
+				{Score: 0, NextReturn: 0.2},
+				{Score: 0.4, NextReturn: -0.1},
+			},
+			want: symbolResult{
+				Symbol:      "X",
+				Days:        4,
+				Trades:      3,
+				HitRate:     1.0 / 3,
+				TotalReturn: 0.9405 - 1,
+				AvgReturn:   -0.0125,
+				MaxDrawdown: (1.1 - 0.9405) / 1.1,
+				IC:          -1 / math.Sqrt(22.5),
+			},
+		},
+		{
+			name: "always right, never drawn down",
+			obs: []observation{
+				{Score: 1, NextReturn: 0.01},
+				{Score: -1, NextReturn: -0.02},
+			},
+			want: symbolResult{
+				Symbol:      "X",
+				Days:        2,
+				Trades:      2,
+				HitRate:     1,
+				TotalReturn: 1.01*1.02 - 1,
+				AvgReturn:   0.015,
+				IC:          1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluate("X", tt.obs)
+			if got.Symbol != tt.want.Symbol || got.Days != tt.want.Days || got.Trades != tt.want.Trades {
+				t.Fatalf("evaluate = %+v, want %+v", got, tt.want)
+			}
+			for _, f := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"HitRate", got.HitRate, tt.want.HitRate},
+				{"TotalReturn", got.TotalReturn, tt.want.TotalReturn},
+				{"AvgReturn", got.AvgReturn, tt.want.AvgReturn},
+				{"MaxDrawdown", got.MaxDrawdown, tt.want.MaxDrawdown},
+				{"IC", got.IC, tt.want.IC},
+			} {
+				if math.Abs(f.got-f.want) > eps {
+					t.Errorf("%s = %v, want %v", f.name, f.got, f.want)
+				}
+			}
+		})
+	}
+}
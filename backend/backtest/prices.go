@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type pricePoint struct {
+	Date  time.Time
+	Close float64
+}
+
+// loadPrices reads a CSV with a date,symbol,close header (column order free)
+// and returns each symbol's closes sorted by date.
+func loadPrices(path string) (map[string][]pricePoint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open prices: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	cols := map[string]int{"date": -1, "symbol": -1, "close": -1}
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(h))
+		if _, ok := cols[name]; ok {
+			cols[name] = i
+		}
+	}
+	for name, idx := range cols {
+		if idx < 0 {
+			return nil, fmt.Errorf("prices CSV is missing a %q column", name)
+		}
+	}
+
+	out := make(map[string][]pricePoint)
+	for line := 2; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		date, err := time.Parse(dateLayout, strings.TrimSpace(rec[cols["date"]]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date: %w", line, err)
+		}
+		closePrice, err := strconv.ParseFloat(strings.TrimSpace(rec[cols["close"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid close: %w", line, err)
+		}
+		symbol := strings.ToUpper(strings.TrimSpace(rec[cols["symbol"]]))
+
+		out[symbol] = append(out[symbol], pricePoint{Date: date, Close: closePrice})
+	}
+
+	for _, series := range out {
+		sort.Slice(series, func(i, j int) bool { return series[i].Date.Before(series[j].Date) })
+	}
+
+	return out, nil
+}
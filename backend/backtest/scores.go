@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"economic_indicator/macro"
+	"economic_indicator/models"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// scoreSource returns the score a symbol had at a given moment.
+// ok is false when no score was available then.
+type scoreSource interface {
+	ScoreAt(ctx context.Context, symbol string, at time.Time) (score float64, ok bool, err error)
+}
+
+// pairCurrencies splits a six-letter FX symbol such as EURUSD into its legs.
+func pairCurrencies(symbol string) (base, quote string, ok bool) {
+	if len(symbol) != 6 {
+		return "", "", false
+	}
+	return symbol[:3], symbol[3:], true
+}
+
+// pitSource recomputes scores from the indicator releases known at each date.
+type pitSource struct {
+	repo            *macro.SnapshotRepository
+	instruments     []macro.InstrumentDef
+	model           *macro.ScoringModel
+	releaseTimeOnly bool
+
+	cache map[time.Time]pitScores
+}
+
+type pitScores struct {
+	currencies  map[string]macro.ScoreBreakdown
+	instruments map[string]macro.InstrumentScore
+}
+
+func newPITSource(
+	repo *macro.SnapshotRepository,
+	instruments []macro.InstrumentDef,
+	model *macro.ScoringModel,
+	releaseTimeOnly bool,
+) *pitSource {
+	return &pitSource{
+		repo:            repo,
+		instruments:     instruments,
+		model:           model,
+		releaseTimeOnly: releaseTimeOnly,
+		cache:           make(map[time.Time]pitScores),
+	}
+}
+
+func (s *pitSource) ScoreAt(ctx context.Context, symbol string, at time.Time) (float64, bool, error) {
+	scores, ok := s.cache[at]
+	if !ok {
+		opts := macro.ScoreOptions{AsOf: at, ReleaseTimeOnly: s.releaseTimeOnly, Model: s.model}
+		currencies, err := s.repo.Scores(ctx, opts)
+		if err != nil && !errors.Is(err, macro.ErrNoSnapshots) {
+			return 0, false, err
+		}
+		scores = pitScores{
+			currencies:  currencies,
+			instruments: macro.BuildInstrumentScores(currencies, s.instruments),
+		}
+		s.cache[at] = scores
+	}
+
+	if inst, ok := scores.instruments[symbol]; ok {
+		return inst.TotalScore, true, nil
+	}
+	if base, quote, ok := pairCurrencies(symbol); ok {
+		b, okB := scores.currencies[base]
+		q, okQ := scores.currencies[quote]
+		if okB && okQ {
+			return b.TotalScore - q.TotalScore, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// storedSource reads the score history written by the score command.
+type storedSource struct {
+	instruments map[string][]timedScore
+	currencies  map[string][]timedScore
+}
+
+type timedScore struct {
+	TS    time.Time
+	Score float64
+}
+
+func loadStoredSource(ctx context.Context, db *bun.DB, model string) (*storedSource, error) {
+	src := &storedSource{
+		instruments: make(map[string][]timedScore),
+		currencies:  make(map[string][]timedScore),
+	}
+
+	var instruments []models.Instrument
+	if err := db.NewSelect().Model(&instruments).Scan(ctx); err != nil {
+		return nil, fmt.Errorf("select instruments: %w", err)
+	}
+	symbols := make(map[int64]string, len(instruments))
+	for _, inst := range instruments {
+		symbols[inst.ID] = inst.Symbol
+	}
+
+	var instRows []models.InstrumentScore
+	err := db.NewSelect().
+		Model(&instRows).
+		Where("model = ?", model).
+		Order("ts ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("select instrument scores: %w", err)
+	}
+	for _, row := range instRows {
+		if symbol, ok := symbols[row.InstrumentID]; ok {
+			src.instruments[symbol] = append(src.instruments[symbol], timedScore{row.TS, row.FinalScore})
+		}
+	}
+
+	var currencies []models.Currency
+	if err := db.NewSelect().Model(&currencies).Scan(ctx); err != nil {
+		return nil, fmt.Errorf("select currencies: %w", err)
+	}
+	codes := make(map[int64]string, len(currencies))
+	for _, c := range currencies {
+		codes[c.ID] = c.Code
+	}
+
+	var curRows []models.CurrencyScore
+	err = db.NewSelect().
+		Model(&curRows).
+		Where("model = ?", model).
+		Order("ts ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("select currency scores: %w", err)
+	}
+	for _, row := range curRows {
+		if code, ok := codes[row.CurrencyID]; ok {
+			src.currencies[code] = append(src.currencies[code], timedScore{row.TS, row.EconScore})
+		}
+	}
+
+	return src, nil
+}
+
+func (s *storedSource) ScoreAt(_ context.Context, symbol string, at time.Time) (float64, bool, error) {
+	if series, ok := s.instruments[symbol]; ok {
+		v, ok := latestAt(series, at)
+		return v, ok, nil
+	}
+	if base, quote, ok := pairCurrencies(symbol); ok {
+		b, okB := latestAt(s.currencies[base], at)
+		q, okQ := latestAt(s.currencies[quote], at)
+		if okB && okQ {
+			return b - q, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// latestAt returns the last score stamped at or before at; series is sorted by TS.
+func latestAt(series []timedScore, at time.Time) (float64, bool) {
+	i := sort.Search(len(series), func(i int) bool { return series[i].TS.After(at) })
+	if i == 0 {
+		return 0, false
+	}
+	return series[i-1].Score, true
+}
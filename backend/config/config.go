@@ -3,14 +3,19 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config database setup
 type Config struct {
-	Addr  string
-	DBDSN string
+	Addr              string
+	DBDSN             string
+	ScoringModelsPath string
+	// EnginePollInterval is how often the scoring engine checks for new
+	// ingest notices; zero turns the engine off.
+	EnginePollInterval time.Duration
 }
 
 // Load load env info
@@ -25,9 +30,16 @@ func Load() *Config {
 		log.Fatal("DB_DSN is required, e.g. user:pass@tcp(localhost:3306)/trading?charset=utf8mb4&parseTime=True&loc=UTC")
 	}
 
+	poll, err := time.ParseDuration(getEnv("ENGINE_POLL_INTERVAL", "30s"))
+	if err != nil {
+		log.Fatalf("ENGINE_POLL_INTERVAL: %v", err)
+	}
+
 	return &Config{
-		Addr:  addr,
-		DBDSN: dsn,
+		Addr:               addr,
+		DBDSN:              dsn,
+		ScoringModelsPath:  getEnv("SCORING_MODELS", "data/scoring_models.json"),
+		EnginePollInterval: poll,
 	}
 }
 
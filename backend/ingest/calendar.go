@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"economic_indicator/macro"
+	"economic_indicator/models"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// CalendarEvent is one scheduled release as returned by a CalendarProvider.
+type CalendarEvent struct {
+	Country    string          `json:"country"`
+	Event      string          `json:"event"`    // e.g. "Non Farm Payrolls"
+	Category   string          `json:"category"` // TradingEconomics category name, e.g. "Non Farm Payrolls"
+	DateTime   time.Time       `json:"datetime"`
+	Importance int             `json:"importance"` // 1 low, 2 medium, 3 high
+	Forecast   *float64        `json:"forecast"`
+	Previous   *float64        `json:"previous"`
+	Raw        json.RawMessage `json:"-"`
+}
+
+// CalendarProvider fetches scheduled releases for a TradingEconomics country
+// name between two dates.
+type CalendarProvider interface {
+	Name() string
+	FetchCalendar(ctx context.Context, country string, from, to time.Time) ([]CalendarEvent, error)
+}
+
+// calendarProviderNames lists the values accepted by -calendar.
+var calendarProviderNames = []string{"te", "file"}
+
+func newCalendarProvider(name string, cfg providerConfig) (CalendarProvider, error) {
+	switch name {
+	case "te":
+		if cfg.TEKey == "" {
+			return nil, fmt.Errorf("TE_KEY env var is required for the TradingEconomics calendar")
+		}
+		return NewTEProvider(cfg.TEKey), nil
+	case "file":
+		if cfg.FilePath == "" {
+			return nil, fmt.Errorf("-calendar-file (or INGEST_CALENDAR_FILE) is required for the file calendar provider")
+		}
+		return NewFileCalendarProvider(cfg.FilePath), nil
+	}
+	return nil, fmt.Errorf("unknown calendar provider %q (want one of %v)", name, calendarProviderNames)
+}
+
+// FetchAndStoreCalendar fetches a country's scheduled releases and stores them
+// in calendar_events under their canonical category. It returns how many
+// events were stored.
+func FetchAndStoreCalendar(ctx context.Context, db *bun.DB, p CalendarProvider, country string, from, to time.Time) (int, error) {
+	events, err := p.FetchCalendar(ctx, country, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for _, ev := range events {
+		if ev.Category != "" {
+			ev.Category, _ = macro.CanonicalCategory(ev.Category)
+		}
+		if err := storeCalendarEvent(ctx, db, p.Name(), ev); err != nil {
+			log.Printf("calendar event store error: %v", err)
+			continue
+		}
+		stored++
+	}
+	return stored, nil
+}
+
+// storeCalendarEvent inserts one event per (country, event, datetime); a
+// re-fetched event refreshes its importance, forecast and previous value.
+func storeCalendarEvent(ctx context.Context, db *bun.DB, source string, ev CalendarEvent) error {
+	if ev.DateTime.IsZero() {
+		return fmt.Errorf("%s %s: missing datetime", ev.Country, ev.Event)
+	}
+	if ev.Event == "" {
+		ev.Event = ev.Category
+	}
+	raw := []byte(ev.Raw)
+	if len(raw) == 0 {
+		raw, _ = json.Marshal(ev)
+	}
+
+	event := models.CalendarEvent{
+		Country:    ev.Country,
+		Event:      ev.Event,
+		DateTime:   ev.DateTime.UTC(),
+		Category:   ev.Category,
+		Importance: ev.Importance,
+		Forecast:   ev.Forecast,
+		Previous:   ev.Previous,
+		Raw:        raw,
+		Source:     source,
+		IngestedAt: time.Now().UTC(),
+	}
+
+	_, err := db.NewInsert().
+		Model(&event).
+		On("DUPLICATE KEY UPDATE").
+		Set("category = VALUES(category)").
+		Set("importance = VALUES(importance)").
+		Set("forecast = VALUES(forecast)").
+		Set("previous = VALUES(previous)").
+		Set("raw = VALUES(raw)").
+		Set("source = VALUES(source)").
+		Exec(ctx)
+	return err
+}
+
+// parseCalendarValue reads a calendar figure as displayed by TE, e.g. "3.2%",
+// "-0.1", "225K" or "$1.2B". Unit suffixes are dropped without rescaling, so
+// forecast and previous stay comparable with each other. Empty means no value.
+func parseCalendarValue(v string) (*float64, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil, nil
+	}
+	v = strings.TrimLeft(v, "$€£¥")
+	v = strings.TrimRight(v, "%KMBT ")
+	f, err := strconv.ParseFloat(strings.ReplaceAll(v, ",", ""), 64)
+	if err != nil {
+		return nil, fmt.Errorf("unrecognised value %q", v)
+	}
+	return &f, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FileCalendarProvider reads scheduled releases from a CSV or JSON file drop,
+// or from every .csv/.json file in a directory.
+//
+// JSON files hold an array of {"country", "event", "category", "datetime",
+// "importance", "forecast", "previous"} objects; CSV files have a header row
+// with the same column names. category, importance, forecast and previous are
+// optional; importance defaults to low. Datetimes use the same layouts as FileProvider.
+type FileCalendarProvider struct {
+	files FileProvider // reused for directory listing
+}
+
+// NewFileCalendarProvider NewFileCalendarProvider
+func NewFileCalendarProvider(path string) *FileCalendarProvider {
+	return &FileCalendarProvider{files: FileProvider{Path: path}}
+}
+
+// Name Name
+func (p *FileCalendarProvider) Name() string { return "file" }
+
+// calendarFileRow is the on-disk shape of one event.
+type calendarFileRow struct {
+	Country    string   `json:"country"`
+	Event      string   `json:"event"`
+	Category   string   `json:"category"`
+	DateTime   string   `json:"datetime"`
+	Importance int      `json:"importance"`
+	Forecast   *float64 `json:"forecast"`
+	Previous   *float64 `json:"previous"`
+}
+
+// FetchCalendar returns the rows whose country matches, case-insensitively,
+// and whose datetime falls in [from, to].
+func (p *FileCalendarProvider) FetchCalendar(ctx context.Context, country string, from, to time.Time) ([]CalendarEvent, error) {
+	files, err := p.files.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []CalendarEvent
+	for _, path := range files {
+		rows, err := readCalendarFileRows(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for i, row := range rows {
+			if !strings.EqualFold(strings.TrimSpace(row.Country), country) {
+				continue
+			}
+			t, err := parseFileDateTime(row.DateTime)
+			if err != nil {
+				return nil, fmt.Errorf("%s row %d: %w", path, i+1, err)
+			}
+			if t.Before(from) || t.After(to) {
+				continue
+			}
+			importance := row.Importance
+			if importance == 0 {
+				importance = 1
+			}
+			raw, _ := json.Marshal(row)
+			out = append(out, CalendarEvent{
+				Country:    country,
+				Event:      strings.TrimSpace(row.Event),
+				Category:   strings.TrimSpace(row.Category),
+				DateTime:   t,
+				Importance: importance,
+				Forecast:   row.Forecast,
+				Previous:   row.Previous,
+				Raw:        raw,
+			})
+		}
+	}
+	return out, nil
+}
+
+func readCalendarFileRows(path string) ([]calendarFileRow, error) {
+	return readRows(path, []string{"country", "event", "datetime"}, func(r csvRow) (calendarFileRow, error) {
+		row := calendarFileRow{
+			Country:  r.get("country"),
+			Event:    r.get("event"),
+			Category: r.get("category"),
+			DateTime: r.get("datetime"),
+		}
+		var err error
+		if row.Importance, err = r.integer("importance"); err != nil {
+			return row, err
+		}
+		if row.Forecast, err = parseCalendarValue(r.get("forecast")); err != nil {
+			return row, fmt.Errorf("forecast: %w", err)
+		}
+		if row.Previous, err = parseCalendarValue(r.get("previous")); err != nil {
+			return row, fmt.Errorf("previous: %w", err)
+		}
+		return row, nil
+	})
+}
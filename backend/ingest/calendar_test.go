@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileCalendarProvider(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "calendar.csv", "Country,Event,Category,DateTime,Importance,Forecast,Previous\n"+
+		"united states,Non Farm Payrolls,Non Farm Payrolls,2024-07-05,3,190K,272K\n"+
+		"United States,Retail Sales MoM,Retail Sales MoM,2024-07-16,,0.0%,-0.1%\n"+
+		"United States,CPI,Inflation Rate,2024-08-14,3,,\n"+
+		"Japan,BoJ Rate Decision,Interest Rate,2024-07-31,3,0.1%,0.1%\n")
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	got, err := NewFileCalendarProvider(dir).FetchCalendar(context.Background(), "United States", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("events = %+v, want the two US events in July", got)
+	}
+	if e := got[0]; e.Event != "Non Farm Payrolls" || e.Importance != 3 || *e.Forecast != 190 || *e.Previous != 272 {
+		t.Errorf("payrolls = %+v", e)
+	}
+	if e := got[1]; e.Importance != 1 || *e.Forecast != 0 || *e.Previous != -0.1 {
+		t.Errorf("retail sales = %+v, want low importance by default", e)
+	}
+
+	_, err = readCalendarFileRows(writeFile(t, dir, "bad.csv", "country,event,datetime,forecast\nJapan,CPI,2024-07-19,n/a\n"))
+	if err == nil || !strings.Contains(err.Error(), "row 2 forecast:") {
+		t.Fatalf("err = %v, want a row 2 forecast error", err)
+	}
+}
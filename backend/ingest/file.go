@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileProvider reads releases from a CSV or JSON file drop, or from every
+// .csv/.json file in a directory.
+//
+// JSON files hold an array of {"country", "category", "value", "previous", "forecast",
+// "datetime"} objects; CSV files have a header row with the same column names.
+// previous and forecast are optional. Datetimes use the TradingEconomics layouts
+// or YYYY-MM-DD.
+type FileProvider struct {
+	Path string
+}
+
+// NewFileProvider NewFileProvider
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{Path: path}
+}
+
+// Name Name
+func (p *FileProvider) Name() string { return "file" }
+
+// fileRow is the on-disk shape of one release.
+type fileRow struct {
+	Country  string   `json:"country"`
+	Category string   `json:"category"`
+	Value    *float64 `json:"value"`
+	Previous *float64 `json:"previous"`
+	Forecast *float64 `json:"forecast"`
+	DateTime string   `json:"datetime"`
+}
+
+// Fetch returns the rows whose country matches, case-insensitively.
+func (p *FileProvider) Fetch(ctx context.Context, country string) ([]Indicator, error) {
+	files, err := p.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Indicator
+	for _, path := range files {
+		rows, err := readFileRows(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for i, row := range rows {
+			if !strings.EqualFold(strings.TrimSpace(row.Country), country) {
+				continue
+			}
+			t, err := parseFileDateTime(row.DateTime)
+			if err != nil {
+				return nil, fmt.Errorf("%s row %d: %w", path, i+1, err)
+			}
+			raw, _ := json.Marshal(row)
+			out = append(out, Indicator{
+				Country:  country,
+				Category: strings.TrimSpace(row.Category),
+				Value:    row.Value,
+				Previous: row.Previous,
+				Forecast: row.Forecast,
+				DateTime: t,
+				Raw:      raw,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (p *FileProvider) files() ([]string, error) {
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{p.Path}, nil
+	}
+
+	entries, err := os.ReadDir(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if !e.IsDir() && (ext == ".csv" || ext == ".json") {
+			files = append(files, filepath.Join(p.Path, e.Name()))
+		}
+	}
+	return files, nil
+}
+
+func readFileRows(path string) ([]fileRow, error) {
+	return readRows(path, []string{"country", "category", "value", "datetime"}, func(r csvRow) (fileRow, error) {
+		row := fileRow{
+			Country:  r.get("country"),
+			Category: r.get("category"),
+			DateTime: r.get("datetime"),
+		}
+		var err error
+		if row.Value, err = r.float("value"); err != nil {
+			return row, err
+		}
+		if row.Previous, err = r.float("previous"); err != nil {
+			return row, err
+		}
+		row.Forecast, err = r.float("forecast")
+		return row, err
+	})
+}
+
+// readRows reads the rows of a file drop: a JSON array decoded into T, or a CSV
+// file with a header row whose required columns must be present. Each CSV record
+// is turned into a T by parse, which addresses fields by column name.
+func readRows[T any](path string, required []string, parse func(csvRow) (T, error)) ([]T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var rows []T
+		if err := json.NewDecoder(f).Decode(&rows); err != nil {
+			return nil, fmt.Errorf("decode: %w", err)
+		}
+		return rows, nil
+	}
+	return readCSVRows(f, required, parse)
+}
+
+func readCSVRows[T any](r io.Reader, required []string, parse func(csvRow) (T, error)) ([]T, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	col := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("missing %q column", name)
+		}
+	}
+
+	rows := make([]T, 0, len(records)-1)
+	for i, rec := range records[1:] {
+		row, err := parse(csvRow{col: col, rec: rec})
+		if err != nil {
+			return nil, fmt.Errorf("row %d %w", i+2, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// csvRow is one CSV record addressed by lower-case header name. Missing
+// columns and short records read as empty.
+type csvRow struct {
+	col map[string]int
+	rec []string
+}
+
+func (r csvRow) get(name string) string {
+	i, ok := r.col[name]
+	if !ok || i >= len(r.rec) {
+		return ""
+	}
+	return strings.TrimSpace(r.rec[i])
+}
+
+// float reads an optional number; empty means nil.
+func (r csvRow) float(name string) (*float64, error) {
+	v := r.get(name)
+	if v == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &f, nil
+}
+
+// integer reads an optional whole number; empty means 0.
+func (r csvRow) integer(name string) (int, error) {
+	v := r.get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return n, nil
+}
+
+func parseFileDateTime(v string) (time.Time, error) {
+	if t, err := parseTEDateTime(v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognised datetime %q", v)
+	}
+	return t.UTC(), nil
+}
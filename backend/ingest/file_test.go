@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile writes content to name in dir and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "releases.csv", "Country,Category,Value,Previous,Forecast,DateTime\n"+
+		"canada,Unemployment Rate ,6.2,6.1,,2024-06-07T12:30:00\n"+
+		"Japan,Unemployment Rate,2.6,2.6,2.6,2024-05-31\n")
+	writeFile(t, dir, "releases.json", `[{"country":"Canada","category":"Inflation Rate","value":2.9,"datetime":"2024-06-25"}]`)
+	writeFile(t, dir, "notes.txt", "ignored")
+
+	var p Provider = NewFileProvider(dir)
+	got, err := p.Fetch(context.Background(), "Canada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name() != "file" || len(got) != 2 {
+		t.Fatalf("%s releases = %+v, want the two Canada rows", p.Name(), got)
+	}
+	if ind := got[0]; ind.Country != "Canada" || ind.Category != "Unemployment Rate" || *ind.Value != 6.2 || *ind.Previous != 6.1 || ind.Forecast != nil ||
+		!ind.DateTime.Equal(time.Date(2024, 6, 7, 12, 30, 0, 0, time.UTC)) || len(ind.Raw) == 0 {
+		t.Errorf("csv release = %+v", ind)
+	}
+	if ind := got[1]; ind.Category != "Inflation Rate" || *ind.Value != 2.9 || ind.Previous != nil || !ind.DateTime.Equal(time.Date(2024, 6, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("json release = %+v", ind)
+	}
+
+	single, err := NewFileProvider(filepath.Join(dir, "releases.json")).Fetch(context.Background(), "Canada")
+	if err != nil || len(single) != 1 {
+		t.Fatalf("single file = %+v, %v", single, err)
+	}
+}
+
+func TestFileProviderErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content, want string
+	}{
+		{"missing column", "r.csv", "country,category,datetime\nCanada,CPI,2024-06-25\n", `missing "value" column`},
+		{"bad value", "r.csv", "country,category,value,datetime\nCanada,CPI,2.9%,2024-06-25\n", "row 2 value:"},
+		{"bad forecast", "r.csv", "country,category,value,forecast,datetime\nCanada,CPI,2.9,n/a,2024-06-25\n", "row 2 forecast:"},
+		{"bad datetime", "r.csv", "country,category,value,datetime\nCanada,CPI,2.9,June\n", `unrecognised datetime "June"`},
+		{"bad json", "r.json", `{"country":"Canada"}`, "decode:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFileProvider(writeFile(t, t.TempDir(), tt.file, tt.content)).Fetch(context.Background(), "Canada")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// fredSeries is a FRED series and the units transform that yields the TE-style value.
+type fredSeries struct {
+	ID    string
+	Units string  // FRED units parameter: "lin" (levels), "pch" (% change), "pc1" (% change from a year ago)
+	Scale float64 // multiplies values into TE's units; 0 means 1
+}
+
+// fredSeriesByCountry maps TradingEconomics country names and category names to
+// FRED series. Only series in the same units as TE's are mapped: FRED has no
+// ISM index for US business confidence (TE's, around 50), and the OECD
+// confidence indicators it does carry sit around 100.
+var fredSeriesByCountry = map[string]map[string]fredSeries{
+	"united states": {
+		"GDP Growth Rate":        {ID: "A191RL1Q225SBEA", Units: "lin"},
+		"Unemployment Rate":      {ID: "UNRATE", Units: "lin"},
+		"Inflation Rate":         {ID: "CPIAUCSL", Units: "pc1"},
+		"Inflation Rate MoM":     {ID: "CPIAUCSL", Units: "pch"},
+		"Interest Rate":          {ID: "DFEDTARU", Units: "lin"},
+		"Consumer Confidence":    {ID: "UMCSENT", Units: "lin"},
+		"Retail Sales MoM":       {ID: "RSAFS", Units: "pch"},
+		"Balance of Trade":       {ID: "BOPGSTB", Units: "lin", Scale: 0.001}, // USD millions to TE's billions
+		"GDP Annual Growth Rate": {ID: "GDPC1", Units: "pc1"},
+	},
+	"euro area": {
+		"Unemployment Rate": {ID: "LRHUTTTTEZM156S", Units: "lin"},
+		"Inflation Rate":    {ID: "CP0000EZ19M086NEST", Units: "pc1"},
+		"Interest Rate":     {ID: "ECBMRRFR", Units: "lin"},
+	},
+	"united kingdom": {
+		"Unemployment Rate": {ID: "LRHUTTTTGBM156S", Units: "lin"},
+		"Inflation Rate":    {ID: "GBRCPIALLMINMEI", Units: "pc1"},
+	},
+	"japan": {
+		"Unemployment Rate": {ID: "LRHUTTTTJPM156S", Units: "lin"},
+		"Inflation Rate":    {ID: "JPNCPIALLMINMEI", Units: "pc1"},
+	},
+}
+
+// FREDProvider reads series observations from the St. Louis Fed FRED API.
+type FREDProvider struct {
+	APIKey  string
+	BaseURL string
+	Client  *http.Client
+	Series  map[string]map[string]fredSeries
+}
+
+// NewFREDProvider NewFREDProvider
+func NewFREDProvider(apiKey string) *FREDProvider {
+	return &FREDProvider{
+		APIKey:  apiKey,
+		BaseURL: "https://api.stlouisfed.org/fred",
+		Client:  &http.Client{Timeout: 30 * time.Second},
+		Series:  fredSeriesByCountry,
+	}
+}
+
+// Name Name
+func (p *FREDProvider) Name() string { return "fred" }
+
+type fredObservations struct {
+	Observations []struct {
+		RealtimeStart string `json:"realtime_start"` // first publication, with output_type 4
+		Date          string `json:"date"`           // start of the reference period
+		Value         string `json:"value"`          // "." when FRED has no value
+	} `json:"observations"`
+}
+
+// Fetch returns the latest observation of every series mapped for the country;
+// countries without mapped series return nothing.
+func (p *FREDProvider) Fetch(ctx context.Context, country string) ([]Indicator, error) {
+	series := p.Series[country]
+	if len(series) == 0 {
+		log.Printf("no FRED series mapped for %s", country)
+		return nil, nil
+	}
+
+	var out []Indicator
+	for category, s := range series {
+		ind, err := p.fetchLatest(ctx, country, category, s)
+		if err != nil {
+			log.Printf("FRED %s (%s): %v", s.ID, category, err)
+			continue
+		}
+		if ind != nil {
+			out = append(out, *ind)
+		}
+	}
+	return out, nil
+}
+
+// fetchLatest reads the two most recent observations as first published: the
+// latest becomes the value and the one before it the previous value. The
+// release is dated by its publication, not by the observation date, which is
+// the start of the reference period (September CPI is dated 1 September but
+// comes out in October).
+func (p *FREDProvider) fetchLatest(ctx context.Context, country, category string, s fredSeries) (*Indicator, error) {
+	q := url.Values{}
+	q.Set("series_id", s.ID)
+	q.Set("api_key", p.APIKey)
+	q.Set("file_type", "json")
+	q.Set("sort_order", "desc")
+	q.Set("limit", "2")
+	// over the whole real-time period, output_type 4 returns each observation's
+	// initial release with realtime_start set to its publication date
+	q.Set("realtime_start", "1776-07-04")
+	q.Set("realtime_end", "9999-12-31")
+	q.Set("output_type", "4")
+	if s.Units != "" {
+		q.Set("units", s.Units)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+"/series/observations?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("FRED status %d", resp.StatusCode)
+	}
+
+	var body fredObservations
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+	if len(body.Observations) == 0 {
+		return nil, nil
+	}
+
+	latest := body.Observations[0]
+	t, err := time.Parse("2006-01-02", latest.RealtimeStart)
+	if err != nil {
+		return nil, fmt.Errorf("publication date: %w", err)
+	}
+
+	ind := &Indicator{
+		Country:  country,
+		Category: category,
+		Value:    parseFREDValue(latest.Value, s.Scale),
+		DateTime: t.UTC(),
+	}
+	if len(body.Observations) > 1 {
+		ind.Previous = parseFREDValue(body.Observations[1].Value, s.Scale)
+	}
+	ind.Raw, _ = json.Marshal(map[string]any{"series_id": s.ID, "units": s.Units, "observations": body.Observations})
+	return ind, nil
+}
+
+func parseFREDValue(v string, scale float64) *float64 {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil
+	}
+	if scale != 0 {
+		f *= scale
+	}
+	return &f
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFREDFetchLatestDatesByPublication(t *testing.T) {
+	var query map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = map[string]string{}
+		for k := range r.URL.Query() {
+			query[k] = r.URL.Query().Get(k)
+		}
+		w.Write([]byte(`{"observations": [
+			{"realtime_start": "2024-10-10", "realtime_end": "9999-12-31", "date": "2024-09-01", "value": "-70400"},
+			{"realtime_start": "2024-09-11", "realtime_end": "9999-12-31", "date": "2024-08-01", "value": "."}
+		]}`))
+	}))
+	defer srv.Close()
+
+	p := NewFREDProvider("key")
+	p.BaseURL = srv.URL
+	ind, err := p.fetchLatest(context.Background(), "united states", "Balance of Trade", fredSeries{ID: "BOPGSTB", Units: "lin", Scale: 0.001})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query["output_type"] != "4" || query["realtime_start"] == "" || query["realtime_end"] == "" {
+		t.Errorf("query %v does not ask for initial releases over the whole real-time period", query)
+	}
+	if want := time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC); !ind.DateTime.Equal(want) {
+		t.Errorf("DateTime = %v, want the publication date %v", ind.DateTime, want)
+	}
+	if ind.Value == nil || *ind.Value != -70.4 {
+		t.Errorf("Value = %v, want -70.4 (millions scaled to billions)", ind.Value)
+	}
+	if ind.Previous != nil {
+		t.Errorf("Previous = %v, want nil for a missing value", *ind.Previous)
+	}
+}
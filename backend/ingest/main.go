@@ -4,41 +4,112 @@ import (
 	"context"
 	"economic_indicator/config"
 	"economic_indicator/db"
+	"economic_indicator/macro"
+	"flag"
 	"log"
 	"os"
+	"time"
 )
 
 func main() {
+	providerName := flag.String("provider", envOr("INGEST_PROVIDER", "te"), "data source: te, fred, file or mock")
+	filePath := flag.String("file", os.Getenv("INGEST_FILE"), "CSV/JSON file or directory for the file provider")
+	policyName := flag.String("policy", os.Getenv("INGEST_POLICY"), "central-bank decision source: file or mock; empty skips policy ingest")
+	policyFile := flag.String("policy-file", os.Getenv("INGEST_POLICY_FILE"), "CSV/JSON file or directory for the file policy provider")
+	calendarName := flag.String("calendar", os.Getenv("INGEST_CALENDAR"), "economic calendar source: te or file; empty skips calendar ingest")
+	calendarFile := flag.String("calendar-file", os.Getenv("INGEST_CALENDAR_FILE"), "CSV/JSON file or directory for the file calendar provider")
+	calendarDays := flag.Int("calendar-days", 14, "days ahead of today to fetch the calendar for")
+	flag.Parse()
+
 	cfg := config.Load()
-	bunDB := db.Open(cfg.DBDSN)
 
-	teAPIKEY := os.Getenv("TE_KEY")
-	if teAPIKEY == "" {
-		log.Fatal("TE_KEY env vars are required for TradingEconomics")
+	provider, err := newProvider(*providerName, providerConfig{
+		TEKey:    os.Getenv("TE_KEY"),
+		FREDKey:  os.Getenv("FRED_KEY"),
+		FilePath: *filePath,
+	})
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	ctx := context.Background()
+	var policy PolicyProvider
+	if *policyName != "" {
+		if policy, err = newPolicyProvider(*policyName, *policyFile); err != nil {
+			log.Fatal(err)
+		}
+	}
 
-	// Currency → Country mapping for TradingEconomics
-	currencyCountries := map[string]string{
-		"USD": "united states",
-		"EUR": "euro area",
-		"GBP": "united kingdom",
-		"JPY": "japan",
-		"AUD": "australia",
-		"NZD": "new zealand",
-		"CHF": "switzerland",
+	var calendar CalendarProvider
+	if *calendarName != "" {
+		calendar, err = newCalendarProvider(*calendarName, providerConfig{TEKey: os.Getenv("TE_KEY"), FilePath: *calendarFile})
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
 
-	for cur, country := range currencyCountries {
-		log.Printf("🔄 Fetching events for %s (%s)", cur, country)
+	bunDB := db.Open(cfg.DBDSN)
+
+	ctx := context.Background()
+	unmapped := make(macro.UnmappedCategories)
 
-		if err := FetchAndStoreCountryIndicators(ctx, bunDB, teAPIKEY, country); err != nil {
+	for cur, country := range macro.CurrencyCountries {
+		log.Printf("🔄 Fetching events for %s (%s) from %s", cur, country, provider.Name())
+
+		n, err := FetchAndStoreCountryIndicators(ctx, bunDB, provider, country, unmapped)
+		if err != nil {
 			log.Printf("❌ failed to ingest %s (%s): %v", cur, country, err)
 		} else {
-			log.Printf("✅ done ingesting %s (%s)", cur, country)
+			log.Printf("✅ done ingesting %s (%s): %d releases", cur, country, n)
+		}
+		if n > 0 {
+			if err := recordNotice(ctx, bunDB, cur, "indicators", n); err != nil {
+				log.Printf("⚠️ failed to record ingest notice for %s: %v", cur, err)
+			}
+		}
+	}
+
+	if len(unmapped) > 0 {
+		log.Printf("⚠️ %d categories are not in the indicator registry and will not be scored:", len(unmapped))
+		for _, c := range unmapped.Sorted() {
+			log.Printf("   %s (%d)", c.Category, c.Count)
+		}
+	}
+
+	if policy != nil {
+		for cur := range macro.CentralBanks {
+			n, err := FetchAndStorePolicyDecisions(ctx, bunDB, policy, cur)
+			if err != nil {
+				log.Printf("❌ failed to ingest %s policy decisions: %v", cur, err)
+			} else {
+				log.Printf("✅ done ingesting %s policy decisions from %s: %d decisions", cur, policy.Name(), n)
+			}
+			if n > 0 {
+				if err := recordNotice(ctx, bunDB, cur, "policy", n); err != nil {
+					log.Printf("⚠️ failed to record ingest notice for %s: %v", cur, err)
+				}
+			}
+		}
+	}
+
+	if calendar != nil {
+		from := time.Now().UTC().Truncate(24 * time.Hour)
+		to := from.AddDate(0, 0, *calendarDays)
+		for cur, country := range macro.CurrencyCountries {
+			n, err := FetchAndStoreCalendar(ctx, bunDB, calendar, country, from, to)
+			if err != nil {
+				log.Printf("❌ failed to ingest %s (%s) calendar: %v", cur, country, err)
+			} else {
+				log.Printf("✅ done ingesting %s (%s) calendar from %s: %d events", cur, country, calendar.Name(), n)
+			}
 		}
 	}
 
 	log.Println("🎉 Completed ingestion for all currencies.")
 }
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"economic_indicator/macro"
+	"economic_indicator/models"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// PolicyDecision is one central-bank rate decision as returned by a PolicyProvider.
+type PolicyDecision struct {
+	Currency    string     `json:"currency"`
+	DateTime    time.Time  `json:"datetime"`
+	Rate        float64    `json:"rate"` // policy rate after the decision, %
+	VotesHike   int        `json:"votes_hike"`
+	VotesHold   int        `json:"votes_hold"`
+	VotesCut    int        `json:"votes_cut"`
+	Guidance    string     `json:"guidance"` // hawkish, dovish or neutral
+	NextMeeting *time.Time `json:"next_meeting"`
+}
+
+// PolicyProvider fetches central-bank decisions for a currency code.
+type PolicyProvider interface {
+	Name() string
+	FetchDecisions(ctx context.Context, currency string) ([]PolicyDecision, error)
+}
+
+// policyProviderNames lists the values accepted by -policy.
+var policyProviderNames = []string{"file", "mock"}
+
+func newPolicyProvider(name, filePath string) (PolicyProvider, error) {
+	switch name {
+	case "file":
+		if filePath == "" {
+			return nil, fmt.Errorf("-policy-file (or INGEST_POLICY_FILE) is required for the file policy provider")
+		}
+		return NewFilePolicyProvider(filePath), nil
+	case "mock":
+		return NewMockPolicyProvider(time.Now().UTC()), nil
+	}
+	return nil, fmt.Errorf("unknown policy provider %q (want one of %v)", name, policyProviderNames)
+}
+
+// FetchAndStorePolicyDecisions fetches a currency's decisions from the provider
+// and stores them in policy_decisions. It returns how many decisions were stored.
+func FetchAndStorePolicyDecisions(ctx context.Context, db *bun.DB, p PolicyProvider, currency string) (int, error) {
+	decisions, err := p.FetchDecisions(ctx, currency)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for _, d := range decisions {
+		added, err := storeDecision(ctx, db, p.Name(), d)
+		if err != nil {
+			log.Printf("policy decision store error: %v", err)
+			continue
+		}
+		if added {
+			stored++
+		}
+	}
+	return stored, nil
+}
+
+// storeDecision stores one decision per (currency, datetime). A re-fetched
+// decision whose details changed is stored as the next revision, with its own
+// ingested_at, so point-in-time reads still see what was known then; an
+// unchanged decision stores nothing. added reports whether a row was inserted.
+func storeDecision(ctx context.Context, db *bun.DB, source string, d PolicyDecision) (added bool, err error) {
+	if d.DateTime.IsZero() {
+		return false, fmt.Errorf("%s decision: missing datetime", d.Currency)
+	}
+	guidance, err := macro.ParseGuidance(d.Guidance)
+	if err != nil {
+		return false, fmt.Errorf("%s decision %s: %w", d.Currency, d.DateTime.Format("2006-01-02"), err)
+	}
+	var next *time.Time
+	if d.NextMeeting != nil {
+		t := d.NextMeeting.UTC()
+		next = &t
+	}
+
+	decision := models.PolicyDecision{
+		Currency:    d.Currency,
+		DateTime:    d.DateTime.UTC(),
+		Rate:        d.Rate,
+		VotesHike:   d.VotesHike,
+		VotesHold:   d.VotesHold,
+		VotesCut:    d.VotesCut,
+		Guidance:    guidance,
+		NextMeeting: next,
+		Source:      source,
+		IngestedAt:  time.Now().UTC(),
+	}
+
+	var latest models.PolicyDecision
+	err = db.NewSelect().
+		Model(&latest).
+		Where("currency = ?", decision.Currency).
+		Where("datetime = ?", decision.DateTime).
+		Order("revision DESC").
+		Limit(1).
+		Scan(ctx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return false, fmt.Errorf("select %s decision %s: %w", d.Currency, d.DateTime.Format("2006-01-02"), err)
+	case sameDecision(latest, decision):
+		return false, nil
+	default:
+		decision.Revision = latest.Revision + 1
+	}
+
+	if _, err := db.NewInsert().Model(&decision).Exec(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sameDecision compares the stored details of two decisions.
+func sameDecision(a, b models.PolicyDecision) bool {
+	sameTime := a.NextMeeting == nil && b.NextMeeting == nil ||
+		a.NextMeeting != nil && b.NextMeeting != nil && a.NextMeeting.Equal(*b.NextMeeting)
+	return a.Rate == b.Rate &&
+		a.VotesHike == b.VotesHike && a.VotesHold == b.VotesHold && a.VotesCut == b.VotesCut &&
+		a.Guidance == b.Guidance && sameTime
+}
+
+// mockMeetingInterval is the gap between mock policy meetings.
+const mockMeetingInterval = 6 * 7 * 24 * time.Hour
+
+// MockPolicyProvider returns a deterministic year of decisions, one every six
+// weeks, ending at the last meeting before Now.
+type MockPolicyProvider struct {
+	Now time.Time
+}
+
+// NewMockPolicyProvider NewMockPolicyProvider
+func NewMockPolicyProvider(now time.Time) *MockPolicyProvider {
+	return &MockPolicyProvider{Now: now}
+}
+
+// Name Name
+func (p *MockPolicyProvider) Name() string { return "mock" }
+
+// FetchDecisions walks the rate in 25bp steps from a hashed starting level; a
+// nine-member committee votes with the decision apart from the odd dissenter.
+func (p *MockPolicyProvider) FetchDecisions(ctx context.Context, currency string) ([]PolicyDecision, error) {
+	// meetings on a fixed six-week grid, so re-runs produce the same dates
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := epoch.Add(p.Now.Sub(epoch) / mockMeetingInterval * mockMeetingInterval)
+
+	const meetings = 9
+	rate := 0.25 * float64(mockHash(currency, "rate", epoch)%20) // 0-4.75%
+	out := make([]PolicyDecision, 0, meetings)
+	for i := meetings - 1; i >= 0; i-- {
+		t := last.Add(-time.Duration(i) * mockMeetingInterval)
+		next := t.Add(mockMeetingInterval)
+
+		d := PolicyDecision{Currency: currency, DateTime: t, NextMeeting: &next, VotesHold: 9, Guidance: macro.GuidanceNeutral}
+		switch h := mockHash(currency, "move", t) % 10; {
+		case h < 2 && rate >= 0.25:
+			rate -= 0.25
+			d.VotesCut, d.VotesHold = 9, 0
+		case h > 7:
+			rate += 0.25
+			d.VotesHike, d.VotesHold = 9, 0
+		}
+		d.Rate = rate
+
+		switch mockHash(currency, "guidance", t) % 4 {
+		case 0:
+			d.Guidance = macro.GuidanceHawkish
+		case 1:
+			d.Guidance = macro.GuidanceDovish
+		}
+		if mockHash(currency, "dissent", t)%3 == 0 { // one member dissents
+			switch {
+			case d.VotesHike == 9:
+				d.VotesHike, d.VotesHold = 8, 1
+			case d.VotesCut == 9:
+				d.VotesCut, d.VotesHold = 8, 1
+			case d.Guidance == macro.GuidanceDovish:
+				d.VotesHold, d.VotesCut = 8, 1
+			default:
+				d.VotesHold, d.VotesHike = 8, 1
+			}
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func mockHash(currency, key string, t time.Time) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(currency + "|" + key + "|" + t.Format("2006-01-02")))
+	return h.Sum64()
+}
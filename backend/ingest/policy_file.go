@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FilePolicyProvider reads central-bank decisions from a CSV or JSON file drop,
+// or from every .csv/.json file in a directory.
+//
+// JSON files hold an array of {"currency", "datetime", "rate", "votes_hike",
+// "votes_hold", "votes_cut", "guidance", "next_meeting"} objects; CSV files have
+// a header row with the same column names. Votes, guidance and next_meeting are
+// optional. Datetimes use the same layouts as FileProvider.
+type FilePolicyProvider struct {
+	files FileProvider // reused for directory listing
+}
+
+// NewFilePolicyProvider NewFilePolicyProvider
+func NewFilePolicyProvider(path string) *FilePolicyProvider {
+	return &FilePolicyProvider{files: FileProvider{Path: path}}
+}
+
+// Name Name
+func (p *FilePolicyProvider) Name() string { return "file" }
+
+// policyFileRow is the on-disk shape of one decision.
+type policyFileRow struct {
+	Currency    string  `json:"currency"`
+	DateTime    string  `json:"datetime"`
+	Rate        float64 `json:"rate"`
+	VotesHike   int     `json:"votes_hike"`
+	VotesHold   int     `json:"votes_hold"`
+	VotesCut    int     `json:"votes_cut"`
+	Guidance    string  `json:"guidance"`
+	NextMeeting string  `json:"next_meeting"`
+}
+
+// FetchDecisions returns the rows whose currency matches, case-insensitively.
+func (p *FilePolicyProvider) FetchDecisions(ctx context.Context, currency string) ([]PolicyDecision, error) {
+	files, err := p.files.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []PolicyDecision
+	for _, path := range files {
+		rows, err := readPolicyFileRows(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for i, row := range rows {
+			if !strings.EqualFold(strings.TrimSpace(row.Currency), currency) {
+				continue
+			}
+			t, err := parseFileDateTime(row.DateTime)
+			if err != nil {
+				return nil, fmt.Errorf("%s row %d: %w", path, i+1, err)
+			}
+			d := PolicyDecision{
+				Currency:  currency,
+				DateTime:  t,
+				Rate:      row.Rate,
+				VotesHike: row.VotesHike,
+				VotesHold: row.VotesHold,
+				VotesCut:  row.VotesCut,
+				Guidance:  row.Guidance,
+			}
+			if row.NextMeeting != "" {
+				next, err := parseFileDateTime(row.NextMeeting)
+				if err != nil {
+					return nil, fmt.Errorf("%s row %d next_meeting: %w", path, i+1, err)
+				}
+				d.NextMeeting = &next
+			}
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func readPolicyFileRows(path string) ([]policyFileRow, error) {
+	return readRows(path, []string{"currency", "datetime", "rate"}, func(r csvRow) (policyFileRow, error) {
+		row := policyFileRow{
+			Currency:    r.get("currency"),
+			DateTime:    r.get("datetime"),
+			Guidance:    r.get("guidance"),
+			NextMeeting: r.get("next_meeting"),
+		}
+		var err error
+		if row.Rate, err = strconv.ParseFloat(r.get("rate"), 64); err != nil {
+			return row, fmt.Errorf("rate: %w", err)
+		}
+		if row.VotesHike, err = r.integer("votes_hike"); err != nil {
+			return row, err
+		}
+		if row.VotesHold, err = r.integer("votes_hold"); err != nil {
+			return row, err
+		}
+		row.VotesCut, err = r.integer("votes_cut")
+		return row, err
+	})
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"economic_indicator/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSameDecision(t *testing.T) {
+	next := time.Date(2024, 7, 31, 18, 0, 0, 0, time.UTC)
+	base := models.PolicyDecision{Rate: 5.5, VotesHold: 9, Guidance: "neutral", NextMeeting: &next}
+	later := next.Add(time.Hour)
+	sameNext := next.In(time.FixedZone("EST", -5*3600))
+
+	tests := []struct {
+		name string
+		edit func(*models.PolicyDecision)
+		want bool
+	}{
+		{"identical", func(*models.PolicyDecision) {}, true},
+		{"next meeting in another zone", func(d *models.PolicyDecision) { d.NextMeeting = &sameNext }, true},
+		{"ingest details ignored", func(d *models.PolicyDecision) { d.ID, d.Source, d.IngestedAt = 7, "file", time.Now() }, true},
+		{"rate", func(d *models.PolicyDecision) { d.Rate = 5.25 }, false},
+		{"votes", func(d *models.PolicyDecision) { d.VotesHold, d.VotesCut = 8, 1 }, false},
+		{"guidance", func(d *models.PolicyDecision) { d.Guidance = "dovish" }, false},
+		{"next meeting moved", func(d *models.PolicyDecision) { d.NextMeeting = &later }, false},
+		{"next meeting dropped", func(d *models.PolicyDecision) { d.NextMeeting = nil }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := base
+			tt.edit(&d)
+			if got := sameDecision(base, d); got != tt.want {
+				t.Fatalf("sameDecision = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilePolicyProvider(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "decisions.csv", "Currency,DateTime,Rate,Votes_Hike,Votes_Hold,Guidance,Next_Meeting\n"+
+		"usd,2024-06-12,5.5,,9,neutral,2024-07-31\n"+
+		"EUR,2024-06-06,4.25,,,dovish,\n")
+	writeFile(t, dir, "decisions.json", `[{"currency":"USD","datetime":"2024-07-31","rate":5.5,"votes_hold":12}]`)
+
+	got, err := NewFilePolicyProvider(dir).FetchDecisions(context.Background(), "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("decisions = %+v, want the two USD rows", got)
+	}
+	next := time.Date(2024, 7, 31, 0, 0, 0, 0, time.UTC)
+	if d := got[0]; d.Currency != "USD" || d.Rate != 5.5 || d.VotesHold != 9 || d.Guidance != "neutral" || d.NextMeeting == nil || !d.NextMeeting.Equal(next) {
+		t.Errorf("csv decision = %+v", d)
+	}
+	if d := got[1]; !d.DateTime.Equal(next) || d.VotesHold != 12 || d.NextMeeting != nil {
+		t.Errorf("json decision = %+v", d)
+	}
+}
+
+func TestReadPolicyFileRowsErrors(t *testing.T) {
+	tests := []struct {
+		name, csv, want string
+	}{
+		{"missing column", "currency,datetime\nUSD,2024-06-12\n", `missing "rate" column`},
+		{"bad rate", "currency,datetime,rate\nUSD,2024-06-12,5.5\nUSD,2024-07-31,high\n", "row 3 rate:"},
+		{"bad votes", "currency,datetime,rate,votes_cut\nUSD,2024-06-12,5.5,two\n", "row 2 votes_cut:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readPolicyFileRows(writeFile(t, t.TempDir(), "decisions.csv", tt.csv))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
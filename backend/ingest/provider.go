@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"economic_indicator/macro"
+	"economic_indicator/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Indicator is one indicator release as returned by a Provider.
+type Indicator struct {
+	Country  string          `json:"country"`
+	Category string          `json:"category"` // TradingEconomics category name, e.g. "Unemployment Rate"
+	Value    *float64        `json:"value"`
+	Previous *float64        `json:"previous"`
+	Forecast *float64        `json:"forecast"` // consensus, when the provider has one
+	DateTime time.Time       `json:"datetime"`
+	Raw      json.RawMessage `json:"-"` // provider payload kept for debugging
+}
+
+// Provider fetches the latest indicator releases for a TradingEconomics country name.
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context, country string) ([]Indicator, error)
+}
+
+// providerNames lists the values accepted by -provider.
+var providerNames = []string{"te", "fred", "file", "mock"}
+
+// providerConfig carries the settings the individual providers need.
+type providerConfig struct {
+	TEKey    string
+	FREDKey  string
+	FilePath string
+}
+
+func newProvider(name string, cfg providerConfig) (Provider, error) {
+	switch name {
+	case "te":
+		if cfg.TEKey == "" {
+			return nil, fmt.Errorf("TE_KEY env var is required for TradingEconomics")
+		}
+		return NewTEProvider(cfg.TEKey), nil
+	case "fred":
+		if cfg.FREDKey == "" {
+			return nil, fmt.Errorf("FRED_KEY env var is required for FRED")
+		}
+		return NewFREDProvider(cfg.FREDKey), nil
+	case "file":
+		if cfg.FilePath == "" {
+			return nil, fmt.Errorf("-file (or INGEST_FILE) is required for the file provider")
+		}
+		return NewFileProvider(cfg.FilePath), nil
+	case "mock":
+		return NewMockProvider(time.Now().UTC()), nil
+	}
+	return nil, fmt.Errorf("unknown provider %q (want one of %v)", name, providerNames)
+}
+
+// FetchAndStoreCountryIndicators fetches a country's indicators from the provider
+// and stores each release in econ_indicators under its canonical category name.
+// Categories missing from the indicator registry are still stored, but counted
+// in unmapped. It returns how many new releases and revisions were stored.
+func FetchAndStoreCountryIndicators(
+	ctx context.Context,
+	db *bun.DB,
+	p Provider,
+	country string,
+	unmapped macro.UnmappedCategories,
+) (int, error) {
+	indicators, err := p.Fetch(ctx, country)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for _, ind := range indicators {
+		category, ok := macro.CanonicalCategory(ind.Category)
+		if !ok {
+			unmapped.Add(ind.Category)
+		}
+		ind.Category = category
+		added, err := storeRelease(ctx, db, p.Name(), ind)
+		if err != nil {
+			log.Printf("indicator store error: %v", err)
+			continue
+		}
+		if added {
+			stored++
+		}
+	}
+	return stored, nil
+}
+
+// storeRelease stores one release per (country, category, datetime). A
+// re-fetched release whose figures changed is stored as the next revision, with
+// its own ingested_at, so point-in-time reads still see the figures known then;
+// unchanged figures store nothing. added reports whether a row was inserted.
+func storeRelease(ctx context.Context, db *bun.DB, source string, ind Indicator) (added bool, err error) {
+	if ind.DateTime.IsZero() {
+		return false, fmt.Errorf("%s %s: missing datetime", ind.Country, ind.Category)
+	}
+	raw := []byte(ind.Raw)
+	if len(raw) == 0 {
+		raw, _ = json.Marshal(ind)
+	}
+
+	indicator := models.EconIndicator{
+		Country:    ind.Country,
+		Category:   ind.Category,
+		Value:      ind.Value,
+		Previous:   ind.Previous,
+		Forecast:   ind.Forecast,
+		DateTime:   ind.DateTime.UTC(),
+		Raw:        raw,
+		Source:     source,
+		IngestedAt: time.Now().UTC(),
+	}
+
+	var latest models.EconIndicator
+	err = db.NewSelect().
+		Model(&latest).
+		Column("value", "previous", "forecast", "revision").
+		Where("country = ?", indicator.Country).
+		Where("category = ?", indicator.Category).
+		Where("datetime = ?", indicator.DateTime).
+		Order("revision DESC").
+		Limit(1).
+		Scan(ctx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return false, fmt.Errorf("select %s %s release: %w", ind.Country, ind.Category, err)
+	case sameFigure(latest.Value, ind.Value) && sameFigure(latest.Previous, ind.Previous) && sameFigure(latest.Forecast, ind.Forecast):
+		return false, nil
+	default:
+		indicator.Revision = latest.Revision + 1
+	}
+
+	if _, err := db.NewInsert().Model(&indicator).Exec(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func sameFigure(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// recordNotice tells a running scoring engine that a currency has new data.
+func recordNotice(ctx context.Context, db *bun.DB, currency, kind string, stored int) error {
+	notice := models.IngestNotice{Currency: currency, Kind: kind, Stored: stored}
+	_, err := db.NewInsert().Model(&notice).Exec(ctx)
+	return err
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"economic_indicator/macro"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+)
+
+// unreachableDB returns a DB whose queries fail straight away.
+func unreachableDB(t *testing.T) *bun.DB {
+	t.Helper()
+	sqldb, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/none?timeout=1s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := bun.NewDB(sqldb, mysqldialect.New())
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// fakeProvider returns fixed indicators, or err, and records the countries asked for.
+type fakeProvider struct {
+	indicators []Indicator
+	err        error
+	asked      []string
+}
+
+func (p *fakeProvider) Name() string { return "fake" }
+
+func (p *fakeProvider) Fetch(ctx context.Context, country string) ([]Indicator, error) {
+	p.asked = append(p.asked, country)
+	return p.indicators, p.err
+}
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      providerConfig
+		wantName string
+		wantErr  string
+	}{
+		{name: "te", cfg: providerConfig{TEKey: "k"}, wantName: "te"},
+		{name: "te", wantErr: "TE_KEY"},
+		{name: "fred", cfg: providerConfig{FREDKey: "k"}, wantName: "fred"},
+		{name: "fred", wantErr: "FRED_KEY"},
+		{name: "file", cfg: providerConfig{FilePath: "releases.csv"}, wantName: "file"},
+		{name: "file", wantErr: "-file"},
+		{name: "mock", wantName: "mock"},
+		{name: "bloomberg", wantErr: `unknown provider "bloomberg" (want one of [te fred file mock])`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.wantErr, func(t *testing.T) {
+			p, err := newProvider(tt.name, tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Name() != tt.wantName {
+				t.Fatalf("provider %q, want %q", p.Name(), tt.wantName)
+			}
+		})
+	}
+}
+
+func TestMockProvider(t *testing.T) {
+	now := time.Date(2024, 6, 17, 9, 30, 0, 0, time.UTC)
+	var p Provider = NewMockProvider(now)
+
+	got, err := p.Fetch(context.Background(), "Japan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(mockBaselines) {
+		t.Fatalf("%d releases, want one per baseline category", len(got))
+	}
+	again, _ := p.Fetch(context.Background(), "Japan")
+	byCategory := make(map[string]Indicator, len(again))
+	for _, ind := range again {
+		byCategory[ind.Category] = ind
+	}
+
+	release := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, ind := range got {
+		b := mockBaselines[ind.Category]
+		if ind.Country != "Japan" || !ind.DateTime.Equal(release) || len(ind.Raw) == 0 {
+			t.Errorf("%s release = %+v", ind.Category, ind)
+		}
+		if ind.Value == nil || ind.Previous == nil || ind.Forecast == nil {
+			t.Fatalf("%s has missing figures", ind.Category)
+		}
+		if *ind.Value < b.Center-b.Spread || *ind.Value > b.Center+b.Spread {
+			t.Errorf("%s value %v outside %v±%v", ind.Category, *ind.Value, b.Center, b.Spread)
+		}
+		if *byCategory[ind.Category].Value != *ind.Value {
+			t.Errorf("%s value not deterministic", ind.Category)
+		}
+		if _, ok := macro.CanonicalCategory(ind.Category); !ok {
+			t.Errorf("%s is not in the indicator registry", ind.Category)
+		}
+	}
+}
+
+func TestFetchAndStoreCountryIndicators(t *testing.T) {
+	db := unreachableDB(t)
+	value := 4.1
+
+	t.Run("fetch error", func(t *testing.T) {
+		p := &fakeProvider{err: errors.New("rate limited")}
+		stored, err := FetchAndStoreCountryIndicators(context.Background(), db, p, "Canada", macro.UnmappedCategories{})
+		if err == nil || err.Error() != "rate limited" || stored != 0 {
+			t.Fatalf("stored %d, err %v, want the fetch error", stored, err)
+		}
+	})
+
+	t.Run("store errors are skipped and unmapped categories counted", func(t *testing.T) {
+		at := time.Date(2024, 6, 7, 12, 30, 0, 0, time.UTC)
+		p := &fakeProvider{indicators: []Indicator{
+			{Country: "Canada", Category: "Unemployment Rate", Value: &value, DateTime: at},
+			{Country: "Canada", Category: "Housing Starts", Value: &value, DateTime: at},
+			{Country: "Canada", Category: "Housing Starts", Value: &value},
+		}}
+		unmapped := macro.UnmappedCategories{}
+		stored, err := FetchAndStoreCountryIndicators(context.Background(), db, p, "Canada", unmapped)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored != 0 {
+			t.Errorf("stored %d releases without a database", stored)
+		}
+		if len(p.asked) != 1 || p.asked[0] != "Canada" {
+			t.Errorf("fetched %v, want Canada once", p.asked)
+		}
+		if len(unmapped) != 1 || unmapped["Housing Starts"] != 2 {
+			t.Errorf("unmapped = %v, want Housing Starts twice", unmapped)
+		}
+	})
+}
@@ -2,96 +2,179 @@ package main
 
 import (
 	"context"
-	"economic_indicator/models"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"time"
-
-	"github.com/uptrace/bun"
 )
 
 // TEIndicator struct
 type TEIndicator struct {
-	Country  string   `json:"Country"`
-	Category string   `json:"Category"`
-	Value    *float64 `json:"Value"`
-	Previous *float64 `json:"Previous"`
-	DateTime string   `json:"DateTime"`
+	Country    string   `json:"Country"`
+	Category   string   `json:"Category"`
+	Value      *float64 `json:"Value"`
+	Previous   *float64 `json:"Previous"`
+	Forecast   *float64 `json:"Forecast"`   // market consensus
+	TEForecast *float64 `json:"TEForecast"` // TE's own forecast, used when there is no consensus
+	DateTime   string   `json:"DateTime"`
+}
+
+// TEProvider reads the TradingEconomics /country API.
+type TEProvider struct {
+	APIKey  string
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewTEProvider NewTEProvider
+func NewTEProvider(apiKey string) *TEProvider {
+	return &TEProvider{
+		APIKey:  apiKey,
+		BaseURL: "https://api.tradingeconomics.com",
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
 }
 
-// FetchAndStoreCountryIndicators fetches indicators for a country
-// from the TradingEconomics /country API and upserts them into econ_indicators.
-func FetchAndStoreCountryIndicators(
-	ctx context.Context,
-	db *bun.DB,
-	apiKey string,
-	country string,
-) error {
+// Name Name
+func (p *TEProvider) Name() string { return "te" }
 
-	url := fmt.Sprintf(
-		"https://api.tradingeconomics.com/country/%s?c=%s",
-		country, apiKey,
-	)
+// Fetch returns every indicator TE lists for the country.
+func (p *TEProvider) Fetch(ctx context.Context, country string) ([]Indicator, error) {
+	endpoint := fmt.Sprintf("%s/country/%s?c=%s", p.BaseURL, url.PathEscape(country), url.QueryEscape(p.APIKey))
 
-	log.Printf("Fetching TE indicators for %s → %s", country, url)
+	log.Printf("Fetching TE indicators for %s", country)
 
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.Client.Do(req)
 	if err != nil {
-		return fmt.Errorf("http error: %w", err)
+		return nil, fmt.Errorf("http error: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("TE status %d", resp.StatusCode)
+		return nil, fmt.Errorf("TE status %d", resp.StatusCode)
 	}
 
-	var indicators []TEIndicator
-	if err := json.NewDecoder(resp.Body).Decode(&indicators); err != nil {
-		return fmt.Errorf("decode: %w", err)
+	var rows []TEIndicator
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
 	}
 
-	for _, ind := range indicators {
-		if err := upsertIndicator(ctx, db, ind); err != nil {
-			log.Printf("indicator upsert error: %v", err)
+	out := make([]Indicator, 0, len(rows))
+	for _, row := range rows {
+		t, err := parseTEDateTime(row.DateTime)
+		if err != nil {
+			log.Printf("skipping TE %s %s: %v", row.Country, row.Category, err)
+			continue
 		}
+		forecast := row.Forecast
+		if forecast == nil {
+			forecast = row.TEForecast
+		}
+		raw, _ := json.Marshal(row)
+		out = append(out, Indicator{
+			Country:  row.Country,
+			Category: row.Category,
+			Value:    row.Value,
+			Previous: row.Previous,
+			Forecast: forecast,
+			DateTime: t,
+			Raw:      raw,
+		})
 	}
+	return out, nil
+}
 
-	return nil
+// TECalendarEvent is one row of the TE /calendar API; figures are display strings.
+type TECalendarEvent struct {
+	CalendarID string `json:"CalendarId"`
+	Date       string `json:"Date"`
+	Country    string `json:"Country"`
+	Category   string `json:"Category"`
+	Event      string `json:"Event"`
+	Previous   string `json:"Previous"`
+	Forecast   string `json:"Forecast"`
+	TEForecast string `json:"TEForecast"`
+	Importance int    `json:"Importance"`
 }
 
-func upsertIndicator(ctx context.Context, db *bun.DB, ind TEIndicator) error {
-	t, _ := time.Parse(time.RFC3339, ind.DateTime)
-	raw, _ := json.Marshal(ind)
-
-	var existing models.EconIndicator
-	err := db.NewSelect().
-		Model(&existing).
-		Where("country = ? AND category = ?", ind.Country, ind.Category).
-		Scan(ctx)
-
-	if err == nil {
-		existing.Value = ind.Value
-		existing.Previous = ind.Previous
-		existing.DateTime = t
-		existing.Raw = raw
-		_, err = db.NewUpdate().Model(&existing).WherePK().Exec(ctx)
-		return err
+// FetchCalendar returns the country's scheduled releases between from and to.
+func (p *TEProvider) FetchCalendar(ctx context.Context, country string, from, to time.Time) ([]CalendarEvent, error) {
+	endpoint := fmt.Sprintf("%s/calendar/country/%s/%s/%s?c=%s", p.BaseURL, url.PathEscape(country),
+		from.Format("2006-01-02"), to.Format("2006-01-02"), url.QueryEscape(p.APIKey))
+
+	log.Printf("Fetching TE calendar for %s", country)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("TE status %d", resp.StatusCode)
 	}
 
-	indicator := models.EconIndicator{
-		Country:    ind.Country,
-		Category:   ind.Category,
-		Value:      ind.Value,
-		Previous:   ind.Previous,
-		DateTime:   t,
-		Raw:        raw,
-		IngestedAt: time.Now().UTC(),
+	var rows []TECalendarEvent
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
 	}
 
-	_, err = db.NewInsert().Model(&indicator).Exec(ctx)
-	return err
+	out := make([]CalendarEvent, 0, len(rows))
+	for _, row := range rows {
+		t, err := parseTEDateTime(row.Date)
+		if err != nil {
+			log.Printf("skipping TE calendar %s %s: %v", row.Country, row.Event, err)
+			continue
+		}
+		forecast, err := parseCalendarValue(row.Forecast)
+		if err == nil && forecast == nil {
+			forecast, err = parseCalendarValue(row.TEForecast)
+		}
+		if err != nil {
+			log.Printf("TE calendar %s %s forecast: %v", row.Country, row.Event, err)
+		}
+		previous, err := parseCalendarValue(row.Previous)
+		if err != nil {
+			log.Printf("TE calendar %s %s previous: %v", row.Country, row.Event, err)
+		}
+		raw, _ := json.Marshal(row)
+		out = append(out, CalendarEvent{
+			Country:    row.Country,
+			Event:      row.Event,
+			Category:   row.Category,
+			DateTime:   t,
+			Importance: row.Importance,
+			Forecast:   forecast,
+			Previous:   previous,
+			Raw:        raw,
+		})
+	}
+	return out, nil
+}
+
+// teDateLayouts lists the DateTime formats TE has been seen to return.
+var teDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+}
+
+func parseTEDateTime(v string) (time.Time, error) {
+	for _, layout := range teDateLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised datetime %q", v)
 }
 
 func abs(f float64) float64 {
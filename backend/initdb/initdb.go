@@ -20,6 +20,9 @@ func main() {
 	if err := createSchema(ctx, database); err != nil {
 		log.Fatalf("createSchema: %v", err)
 	}
+	if err := migrate(ctx, database); err != nil {
+		log.Fatalf("migrate: %v", err)
+	}
 
 	log.Println("✅ Schema created / updated")
 }
@@ -32,6 +35,10 @@ func createSchema(ctx context.Context, database *bun.DB) error {
 		(*models.Instrument)(nil),
 		(*models.InstrumentScore)(nil),
 		(*models.EconIndicator)(nil),
+		(*models.PolicyDecision)(nil),
+		(*models.CalendarEvent)(nil),
+		(*models.IngestNotice)(nil),
+		(*models.ScoreChangeEvent)(nil),
 	}
 
 	for _, m := range modelsToCreate {
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// migration is one idempotent change that brings a table created by an older
+// version up to date; createSchema's IfNotExists leaves existing tables alone.
+type migration struct {
+	name string
+	run  func(ctx context.Context, db *bun.DB) error
+}
+
+// migrations run in order after createSchema, on every initdb.
+var migrations = []migration{
+	{"currency_scores model column", addColumn("currency_scores", "model", "VARCHAR(255) NOT NULL DEFAULT 'default'")},
+	{"currency_scores components column", addColumn("currency_scores", "components", "JSON")},
+	{"currency_scores explanation column", addColumn("currency_scores", "explanation", "TEXT")},
+	{"instrument_scores model column", addColumn("instrument_scores", "model", "VARCHAR(255) NOT NULL DEFAULT 'default'")},
+	{"instrument_scores components column", addColumn("instrument_scores", "components", "JSON")},
+	{"instrument_scores explanation column", addColumn("instrument_scores", "explanation", "TEXT")},
+	{"instruments driver_currency column", addColumn("instruments", "driver_currency", "VARCHAR(255)")},
+	{"instruments quote_currency column", addColumn("instruments", "quote_currency", "VARCHAR(255)")},
+	{"instruments drivers column", addColumn("instruments", "drivers", "JSON")},
+	{"econ_indicators forecast column", addColumn("econ_indicators", "forecast", "DOUBLE")},
+	{"econ_indicators source column", addColumn("econ_indicators", "source", "VARCHAR(255)")},
+	{"econ_indicators revision column", addColumn("econ_indicators", "revision", "INT NOT NULL DEFAULT 0")},
+	{"econ_indicators one row per release revision", uniqueReleaseRevisions},
+	{"policy_decisions revision column", addColumn("policy_decisions", "revision", "INT NOT NULL DEFAULT 0")},
+	{"policy_decisions one row per decision revision", uniqueDecisionRevisions},
+}
+
+func migrate(ctx context.Context, db *bun.DB) error {
+	for _, m := range migrations {
+		if err := m.run(ctx, db); err != nil {
+			return fmt.Errorf("migration %q: %w", m.name, err)
+		}
+	}
+	return nil
+}
+
+// addColumn adds a column with the given SQL definition unless it exists.
+func addColumn(table, column, definition string) func(ctx context.Context, db *bun.DB) error {
+	return func(ctx context.Context, db *bun.DB) error {
+		exists, err := columnExists(ctx, db, table, column)
+		if err != nil || exists {
+			return err
+		}
+		log.Printf("adding %s.%s", table, column)
+		_, err = db.ExecContext(ctx, "ALTER TABLE ? ADD COLUMN ? "+definition, bun.Ident(table), bun.Ident(column))
+		return err
+	}
+}
+
+// uniqueReleaseRevisions replaces the old append-only econ_indicators layout,
+// where every ingest run stored another copy of each release, with one row per
+// (country, category, datetime, revision). Copies repeating an earlier row's
+// figures are deleted, keeping the first one and so the time it was first
+// ingested; the remaining rows of a release are numbered as revisions in id order.
+func uniqueReleaseRevisions(ctx context.Context, db *bun.DB) error {
+	const index = "country_category_datetime_revision"
+	exists, err := indexExists(ctx, db, "econ_indicators", index)
+	if err != nil || exists {
+		return err
+	}
+
+	res, err := db.ExecContext(ctx, `DELETE e FROM econ_indicators e
+		JOIN econ_indicators k
+		  ON k.country = e.country AND k.category = e.category AND k.datetime = e.datetime
+		 AND k.id < e.id
+		 AND k.value <=> e.value AND k.previous <=> e.previous AND k.forecast <=> e.forecast`)
+	if err != nil {
+		return fmt.Errorf("delete duplicate releases: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("deleted %d duplicate econ_indicators rows", n)
+	}
+
+	_, err = db.ExecContext(ctx, `UPDATE econ_indicators e
+		JOIN (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY country, category, datetime ORDER BY id) - 1 AS rev
+			FROM econ_indicators
+		) r ON r.id = e.id
+		SET e.revision = r.rev`)
+	if err != nil {
+		return fmt.Errorf("number revisions: %w", err)
+	}
+
+	// tables created with the first unique key, before revisions were kept
+	return replaceUniqueIndex(ctx, db, "econ_indicators", "country_category_datetime", index, "country", "category", "datetime", "revision")
+}
+
+// uniqueDecisionRevisions moves policy_decisions from one row per decision,
+// refreshed in place, to one row per revision. Existing rows are unique per
+// (currency, datetime) and become revision 0.
+func uniqueDecisionRevisions(ctx context.Context, db *bun.DB) error {
+	const index = "currency_datetime_revision"
+	exists, err := indexExists(ctx, db, "policy_decisions", index)
+	if err != nil || exists {
+		return err
+	}
+	return replaceUniqueIndex(ctx, db, "policy_decisions", "currency_datetime", index, "currency", "datetime", "revision")
+}
+
+// replaceUniqueIndex drops the old unique index, if there, and creates the new one.
+func replaceUniqueIndex(ctx context.Context, db *bun.DB, table, old, index string, columns ...string) error {
+	exists, err := indexExists(ctx, db, table, old)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if _, err := db.ExecContext(ctx, "DROP INDEX ? ON ?", bun.Ident(old), bun.Ident(table)); err != nil {
+			return fmt.Errorf("drop old unique index %s: %w", old, err)
+		}
+	}
+
+	log.Printf("creating unique index %s on %s", index, table)
+	_, err = db.ExecContext(ctx, "CREATE UNIQUE INDEX ? ON ? (?)", bun.Ident(index), bun.Ident(table), bun.Safe(identList(columns)))
+	return err
+}
+
+func identList(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = "`" + c + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func columnExists(ctx context.Context, db *bun.DB, table, column string) (bool, error) {
+	var n int
+	err := db.NewSelect().
+		TableExpr("information_schema.columns").
+		ColumnExpr("COUNT(*)").
+		Where("table_schema = DATABASE()").
+		Where("table_name = ?", table).
+		Where("column_name = ?", column).
+		Scan(ctx, &n)
+	if err != nil {
+		return false, fmt.Errorf("look up column %s.%s: %w", table, column, err)
+	}
+	return n > 0, nil
+}
+
+func indexExists(ctx context.Context, db *bun.DB, table, index string) (bool, error) {
+	var n int
+	err := db.NewSelect().
+		TableExpr("information_schema.statistics").
+		ColumnExpr("COUNT(*)").
+		Where("table_schema = DATABASE()").
+		Where("table_name = ?", table).
+		Where("index_name = ?", index).
+		Scan(ctx, &n)
+	if err != nil {
+		return false, fmt.Errorf("look up index %s.%s: %w", table, index, err)
+	}
+	return n > 0, nil
+}
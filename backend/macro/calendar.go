@@ -0,0 +1,154 @@
+package macro
+
+import (
+	"context"
+	"economic_indicator/models"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Calendar importance levels, as used by TradingEconomics.
+const (
+	ImportanceLow    = 1
+	ImportanceMedium = 2
+	ImportanceHigh   = 3
+)
+
+// DefaultCalendarSpan is how far ahead the calendar looks when no end is given.
+const DefaultCalendarSpan = 7 * day
+
+// CalendarEvent is one scheduled release.
+type CalendarEvent struct {
+	Currency   string    `json:"currency"`
+	Country    string    `json:"country"`
+	Event      string    `json:"event"`
+	Category   string    `json:"category,omitempty"`
+	Indicator  string    `json:"indicator,omitempty"` // registry key, when the category is known
+	DateTime   time.Time `json:"datetime"`
+	Importance int       `json:"importance"`
+	Forecast   *float64  `json:"forecast"`
+	Previous   *float64  `json:"previous"`
+}
+
+// CalendarOptions selects calendar events.
+type CalendarOptions struct {
+	Currencies    []string  // empty means every tracked currency
+	From          time.Time // zero means now
+	To            time.Time // zero means From + DefaultCalendarSpan
+	MinImportance int
+	// KnownAt, when set, hides events ingested after it, for point-in-time scores.
+	KnownAt time.Time
+}
+
+// Calendar returns scheduled events in [From, To], soonest first.
+func (r *SnapshotRepository) Calendar(ctx context.Context, opts CalendarOptions) ([]CalendarEvent, error) {
+	if r.DB == nil {
+		return nil, fmt.Errorf("no database configured")
+	}
+
+	from := opts.From
+	if from.IsZero() {
+		from = time.Now().UTC()
+	}
+	to := opts.To
+	if to.IsZero() {
+		to = from.Add(DefaultCalendarSpan)
+	}
+
+	countries := make(map[string]string) // TE country name → currency
+	for code, country := range CurrencyCountries {
+		countries[country] = code
+	}
+	if len(opts.Currencies) > 0 {
+		countries = make(map[string]string, len(opts.Currencies))
+		for _, code := range opts.Currencies {
+			code = strings.ToUpper(code)
+			country, ok := CurrencyCountries[code]
+			if !ok {
+				return nil, fmt.Errorf("unknown currency %q", code)
+			}
+			countries[country] = code
+		}
+	}
+	names := make([]string, 0, len(countries))
+	for country := range countries {
+		names = append(names, country)
+	}
+
+	var rows []models.CalendarEvent
+	q := r.DB.NewSelect().
+		Model(&rows).
+		Where("LOWER(country) IN (?)", bun.In(names)).
+		Where("datetime >= ?", from.UTC()).
+		Where("datetime <= ?", to.UTC()).
+		Order("datetime ASC", "importance DESC")
+	if opts.MinImportance > 0 {
+		q = q.Where("importance >= ?", opts.MinImportance)
+	}
+	if !opts.KnownAt.IsZero() {
+		q = q.Where("ingested_at <= ?", opts.KnownAt.UTC())
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("select calendar events: %w", err)
+	}
+
+	out := make([]CalendarEvent, 0, len(rows))
+	for _, row := range rows {
+		ev := CalendarEvent{
+			Currency:   countries[strings.ToLower(row.Country)],
+			Country:    row.Country,
+			Event:      row.Event,
+			Category:   row.Category,
+			DateTime:   row.DateTime.UTC(),
+			Importance: row.Importance,
+			Forecast:   row.Forecast,
+			Previous:   row.Previous,
+		}
+		if def, ok := LookupIndicator(row.Category); ok {
+			ev.Indicator = def.Key
+		}
+		out = append(out, ev)
+	}
+	return out, nil
+}
+
+// nextHighImpactHorizon bounds how far ahead the next high-impact event is searched.
+const nextHighImpactHorizon = 30 * day
+
+// NextHighImpactEvents returns each currency's first high-importance event after
+// opts' reference time. Point-in-time requests only see events ingested by AsOf.
+func (r *SnapshotRepository) NextHighImpactEvents(ctx context.Context, opts ScoreOptions) (map[string]CalendarEvent, error) {
+	from := opts.referenceTime()
+	copts := CalendarOptions{From: from, To: from.Add(nextHighImpactHorizon), MinImportance: ImportanceHigh}
+	if opts.PointInTime() && !opts.ReleaseTimeOnly {
+		copts.KnownAt = opts.AsOf
+	}
+
+	events, err := r.Calendar(ctx, copts)
+	if err != nil {
+		return nil, err
+	}
+
+	next := make(map[string]CalendarEvent)
+	for _, ev := range events { // soonest first
+		if _, ok := next[ev.Currency]; !ok {
+			next[ev.Currency] = ev
+		}
+	}
+	return next, nil
+}
+
+// applyNextEvents attaches each currency's next high-impact event to its score.
+func applyNextEvents(scores map[string]ScoreBreakdown, next map[string]CalendarEvent) {
+	for code, ev := range next {
+		s, ok := scores[code]
+		if !ok {
+			continue
+		}
+		s.NextHighImpactEvent = &ev
+		scores[code] = s
+	}
+}
@@ -0,0 +1,120 @@
+package macro
+
+import (
+	"context"
+	"economic_indicator/models"
+	"fmt"
+	"log"
+
+	"github.com/uptrace/bun"
+)
+
+// AssetTypes lists the asset classes scoreInstrument has rules for.
+var AssetTypes = []string{"index", "metal", "fx", "energy", "bond", "crypto"}
+
+// InstrumentDef describes a scored instrument and the currencies driving it.
+type InstrumentDef struct {
+	Symbol    string
+	AssetType string
+	BaseFX    string             // which macro country code/currency drives it
+	QuoteFX   string             // fx only: the quote currency
+	Drivers   []InstrumentDriver // further weighted inputs blended into the score
+}
+
+// DefaultInstruments is used when the instruments table has no usable rows.
+var DefaultInstruments = []InstrumentDef{
+	{Symbol: "US500", AssetType: "index", BaseFX: "USD"},
+	{Symbol: "US100", AssetType: "index", BaseFX: "USD"},
+	{Symbol: "JP225", AssetType: "index", BaseFX: "JPY", Drivers: []InstrumentDriver{ // Nikkei 225, rides USD/JPY
+		{Currency: "USD", Weight: 0.5},
+		{Currency: "JPY", Weight: -0.5},
+	}},
+	{Symbol: "XAUUSD", AssetType: "metal", BaseFX: "USD", Drivers: []InstrumentDriver{
+		{Currency: GlobalDriver, Component: "inflation", Weight: -0.3}, // above-target inflation worldwide
+	}},
+	{Symbol: "XAGUSD", AssetType: "metal", BaseFX: "USD", Drivers: []InstrumentDriver{
+		{Currency: GlobalDriver, Component: "manufacturing_pmi", Weight: 0.3}, // industrial demand
+	}},
+	{Symbol: "USOIL", AssetType: "energy", BaseFX: "USD", Drivers: []InstrumentDriver{ // WTI
+		{Currency: GlobalDriver, Component: "manufacturing_pmi", Weight: 0.3},
+	}},
+	{Symbol: "UKOIL", AssetType: "energy", BaseFX: "USD", Drivers: []InstrumentDriver{ // Brent, priced off global demand
+		{Currency: GlobalDriver, Component: "manufacturing_pmi", Weight: 0.5},
+	}},
+	{Symbol: "US10Y", AssetType: "bond", BaseFX: "USD"},
+	{Symbol: "DE10Y", AssetType: "bond", BaseFX: "EUR"}, // Bund
+	{Symbol: "BTCUSD", AssetType: "crypto", BaseFX: "USD"},
+	{Symbol: "EURUSD", AssetType: "fx", BaseFX: "EUR", QuoteFX: "USD"},
+	{Symbol: "GBPUSD", AssetType: "fx", BaseFX: "GBP", QuoteFX: "USD"},
+	{Symbol: "USDJPY", AssetType: "fx", BaseFX: "USD", QuoteFX: "JPY"},
+	{Symbol: "AUDUSD", AssetType: "fx", BaseFX: "AUD", QuoteFX: "USD"},
+	{Symbol: "USDCHF", AssetType: "fx", BaseFX: "USD", QuoteFX: "CHF"},
+	{Symbol: "GBPJPY", AssetType: "fx", BaseFX: "GBP", QuoteFX: "JPY"},
+}
+
+// InstrumentRepository reads the instrument catalogue from the instruments table.
+type InstrumentRepository struct {
+	DB *bun.DB
+}
+
+// NewInstrumentRepository NewInstrumentRepository
+func NewInstrumentRepository(db *bun.DB) *InstrumentRepository {
+	return &InstrumentRepository{DB: db}
+}
+
+// List returns the instruments to score. Rows without a driving currency (or,
+// for fx, a quote currency) are skipped; an empty catalogue falls back to DefaultInstruments.
+func (r *InstrumentRepository) List(ctx context.Context) ([]InstrumentDef, error) {
+	if r.DB == nil {
+		return DefaultInstruments, nil
+	}
+
+	var rows []models.Instrument
+	if err := r.DB.NewSelect().Model(&rows).Order("symbol ASC").Scan(ctx); err != nil {
+		return nil, fmt.Errorf("select instruments: %w", err)
+	}
+
+	defs := make([]InstrumentDef, 0, len(rows))
+	for _, row := range rows {
+		if row.DriverCurrency == "" {
+			log.Printf("instrument %s has no driver currency, skipping", row.Symbol)
+			continue
+		}
+		if row.AssetType == "fx" && row.QuoteCurrency == "" {
+			log.Printf("fx instrument %s has no quote currency, skipping", row.Symbol)
+			continue
+		}
+		defs = append(defs, InstrumentDefFromModel(row))
+	}
+
+	if len(defs) == 0 {
+		return DefaultInstruments, nil
+	}
+	return defs, nil
+}
+
+// InstrumentDefFromModel converts an instruments row into a scoring definition.
+func InstrumentDefFromModel(row models.Instrument) InstrumentDef {
+	drivers := make([]InstrumentDriver, 0, len(row.Drivers))
+	for _, d := range row.Drivers {
+		drivers = append(drivers, InstrumentDriver{Currency: d.Currency, Component: d.Component, Weight: d.Weight})
+	}
+
+	return InstrumentDef{
+		Symbol:    row.Symbol,
+		AssetType: row.AssetType,
+		BaseFX:    row.DriverCurrency,
+		QuoteFX:   row.QuoteCurrency,
+		Drivers:   drivers,
+	}
+}
+
+// ValidAssetType reports whether scoreInstrument knows the asset type.
+func ValidAssetType(assetType string) bool {
+	for _, t := range AssetTypes {
+		if t == assetType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package macro
+
+import "strings"
+
+// CurrencyCountries maps currency codes to TradingEconomics country names.
+var CurrencyCountries = map[string]string{
+	"USD": "united states",
+	"EUR": "euro area",
+	"GBP": "united kingdom",
+	"JPY": "japan",
+	"AUD": "australia",
+	"NZD": "new zealand",
+	"CHF": "switzerland",
+}
+
+// CurrencyForCountry returns the currency code for a TradingEconomics country name.
+func CurrencyForCountry(country string) (string, bool) {
+	name := strings.ToLower(strings.TrimSpace(country))
+	for code, c := range CurrencyCountries {
+		if c == name {
+			return code, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,113 @@
+package macro
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// GlobalDriver is the pseudo-currency averaging every scored currency.
+const GlobalDriver = "GLOBAL"
+
+// InstrumentDriver adds Weight × (a currency's total score or one of its
+// components) to an instrument. Components are scored so that positive is
+// supportive for the currency; use a negative weight to reverse the effect.
+type InstrumentDriver struct {
+	Currency  string  `json:"currency"`            // currency code or GLOBAL
+	Component string  `json:"component,omitempty"` // e.g. "inflation"; empty means the total score
+	Weight    float64 `json:"weight"`              // signed, e.g. -1.0 for XAUUSD's USD leg
+}
+
+// Key names the driver's entry in InstrumentScore.Components.
+func (d InstrumentDriver) Key() string {
+	key := "driver_" + strings.ToLower(d.Currency)
+	if d.Component != "" {
+		key += "_" + d.Component
+	}
+	return key
+}
+
+// driverValue reads the driver's input from the scores; GLOBAL averages all currencies.
+func driverValue(d InstrumentDriver, scoresByCountry map[string]ScoreBreakdown) (float64, bool) {
+	pick := func(s ScoreBreakdown) (float64, bool) {
+		if d.Component == "" {
+			return s.TotalScore, true
+		}
+		v, ok := s.Components[d.Component]
+		return v, ok
+	}
+
+	if d.Currency != GlobalDriver {
+		s, ok := scoresByCountry[d.Currency]
+		if !ok {
+			return 0, false
+		}
+		return pick(s)
+	}
+
+	var sum float64
+	var count float64
+	for _, s := range scoresByCountry {
+		if v, ok := pick(s); ok {
+			sum += v
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / count, true
+}
+
+// driverContributions returns weight × value per driver key and the sum of
+// absolute weights of the drivers that had data.
+func driverContributions(drivers []InstrumentDriver, scoresByCountry map[string]ScoreBreakdown) (map[string]float64, float64) {
+	out := make(map[string]float64, len(drivers))
+	var weightSum float64
+	for _, d := range drivers {
+		v, ok := driverValue(d, scoresByCountry)
+		if !ok || d.Weight == 0 {
+			continue
+		}
+		out[d.Key()] += d.Weight * v
+		weightSum += math.Abs(d.Weight)
+	}
+	return out, weightSum
+}
+
+func explainDrivers(drivers []InstrumentDriver, contributions map[string]float64) string {
+	var supports, drags []string
+	for _, d := range drivers {
+		c, ok := contributions[d.Key()]
+		if !ok {
+			continue
+		}
+		label := driverLabel(d)
+		switch {
+		case c > 0.05:
+			supports = append(supports, fmt.Sprintf("%s (%+.2f)", label, c))
+		case c < -0.05:
+			drags = append(drags, fmt.Sprintf("%s (%+.2f)", label, c))
+		}
+	}
+
+	text := ""
+	if len(supports) > 0 {
+		text += " Cross-market drivers adding support: " + joinWithAnd(supports) + "."
+	}
+	if len(drags) > 0 {
+		text += " Cross-market drivers weighing on it: " + joinWithAnd(drags) + "."
+	}
+	return text
+}
+
+func driverLabel(d InstrumentDriver) string {
+	who := d.Currency + " macro"
+	if d.Currency == GlobalDriver {
+		who = "global"
+	}
+	if d.Component == "" {
+		return who + " score"
+	}
+	return who + " " + kToLabel(d.Component)
+}
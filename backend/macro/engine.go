@@ -0,0 +1,504 @@
+package macro
+
+import (
+	"context"
+	"economic_indicator/models"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kinds of score a ScoreChange can refer to.
+const (
+	ChangeCurrency   = "currency"
+	ChangeInstrument = "instrument"
+	ChangePair       = "pair"
+)
+
+// TriggerRefresh is the TriggeredBy value of changes found by the periodic full
+// rescore, which moves scores through ages and upcoming events rather than new data.
+const TriggerRefresh = "refresh"
+
+// Engine defaults.
+const (
+	DefaultEnginePollInterval = 30 * time.Second
+	DefaultEngineRefresh      = time.Hour // full rescore for age-dependent parts: decay, staleness, next events
+	DefaultEngineMinDelta     = 0.001     // scores are rounded to 3 decimals
+)
+
+// ScoreChange is a score the engine saw move after new data arrived.
+type ScoreChange struct {
+	TS          time.Time `json:"ts"`
+	Kind        string    `json:"kind"`            // one of the Change* kinds
+	Subject     string    `json:"subject"`         // currency code, instrument symbol or pair, e.g. EURUSD
+	Base        string    `json:"base,omitempty"`  // pair changes only
+	Quote       string    `json:"quote,omitempty"` // pair changes only
+	Model       string    `json:"model"`
+	Before      *float64  `json:"before"` // nil for a first score
+	After       float64   `json:"after"`
+	Delta       float64   `json:"delta"`
+	TriggeredBy []string  `json:"triggered_by"` // currencies whose new data caused the rescore, or TriggerRefresh
+}
+
+// Engine keeps the live snapshots and scores in memory and rescores only the
+// currencies that received new data, plus the instruments and pairs built on
+// them. It learns about new data from ingest_notices rows, polled every
+// PollInterval, or from Notify. Every RefreshInterval all currencies are
+// rescored, since ages and upcoming events move with the clock. Every score
+// that moves by at least MinDelta is recorded in score_change_events and,
+// with the new releases behind it, published to Subscribe callers.
+type Engine struct {
+	Snapshots       *SnapshotRepository
+	Instruments     *InstrumentRepository
+	Options         ScoreOptions // AsOf must be zero: the engine tracks the latest data
+	PollInterval    time.Duration
+	RefreshInterval time.Duration
+	MinDelta        float64
+
+	notify  chan []string
+	running atomic.Bool
+
+	mu          sync.RWMutex
+	snapshots   map[string]MacroSnapshot
+	scores      map[string]ScoreBreakdown
+	instruments map[string]InstrumentScore
+	pairs       map[currencyPair]float64
+	lastNotice  int64
+
+	subscribers subscribers
+}
+
+// NewEngine NewEngine
+func NewEngine(snapshots *SnapshotRepository, instruments *InstrumentRepository, opts ScoreOptions) *Engine {
+	if opts.Model == nil {
+		opts.Model = DefaultScoringModel()
+	}
+	return &Engine{
+		Snapshots:       snapshots,
+		Instruments:     instruments,
+		Options:         opts,
+		PollInterval:    DefaultEnginePollInterval,
+		RefreshInterval: DefaultEngineRefresh,
+		MinDelta:        DefaultEngineMinDelta,
+		notify:          make(chan []string, 16),
+	}
+}
+
+// Notify asks the engine to rescore the given currencies, e.g. after an
+// in-process ingest. It does not block.
+func (e *Engine) Notify(codes ...string) {
+	select {
+	case e.notify <- codes:
+	default:
+		log.Printf("scoring engine: notification for %v dropped, queue full", codes)
+	}
+}
+
+// RescoreAll asks the engine to rescore every currency and so rebuild every
+// instrument score, e.g. after the instrument catalogue changed. It does not block.
+func (e *Engine) RescoreAll() {
+	e.Notify(e.currencies()...)
+}
+
+// Start scores everything once. Callers that need a working engine before
+// serving it, e.g. to retry while the database comes up, call Start before Run.
+func (e *Engine) Start(ctx context.Context) error {
+	if e.Options.PointInTime() {
+		return fmt.Errorf("scoring engine: as-of options are not supported")
+	}
+	if err := e.start(ctx); err != nil {
+		return err
+	}
+	e.running.Store(true)
+	return nil
+}
+
+// Running reports whether the engine has started and Run has not returned.
+func (e *Engine) Running() bool {
+	return e.running.Load()
+}
+
+// Run starts the engine unless Start already has, then rescores on notices
+// until ctx is done.
+func (e *Engine) Run(ctx context.Context) error {
+	if !e.Running() {
+		if err := e.Start(ctx); err != nil {
+			return err
+		}
+	}
+	defer e.running.Store(false)
+
+	var poll <-chan time.Time
+	if e.Snapshots.DB != nil && e.PollInterval > 0 {
+		ticker := time.NewTicker(e.PollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+	var refresh <-chan time.Time
+	if e.RefreshInterval > 0 {
+		ticker := time.NewTicker(e.RefreshInterval)
+		defer ticker.Stop()
+		refresh = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case codes := <-e.notify:
+			e.rescoreAndLog(ctx, codes, nil)
+		case <-poll:
+			codes, err := e.pollNotices(ctx)
+			if err != nil {
+				log.Printf("scoring engine: poll notices: %v", err)
+				continue
+			}
+			if len(codes) > 0 {
+				e.rescoreAndLog(ctx, codes, nil)
+			}
+		case <-refresh:
+			e.rescoreAndLog(ctx, e.currencies(), []string{TriggerRefresh})
+		}
+	}
+}
+
+// Scores returns the engine's currency scores when opts asks for what the
+// engine tracks (latest data, same model and scoring settings); ok is false otherwise.
+func (e *Engine) Scores(opts ScoreOptions) (map[string]ScoreBreakdown, bool) {
+	if !e.serves(opts) {
+		return nil, false
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.scores == nil {
+		return nil, false
+	}
+	out := make(map[string]ScoreBreakdown, len(e.scores))
+	for code, s := range e.scores {
+		out[code] = s
+	}
+	return out, true
+}
+
+// InstrumentScores is Scores for instruments.
+func (e *Engine) InstrumentScores(opts ScoreOptions) (map[string]InstrumentScore, bool) {
+	if !e.serves(opts) {
+		return nil, false
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.instruments == nil {
+		return nil, false
+	}
+	out := make(map[string]InstrumentScore, len(e.instruments))
+	for symbol, s := range e.instruments {
+		out[symbol] = s
+	}
+	return out, true
+}
+
+func (e *Engine) serves(opts ScoreOptions) bool {
+	model := opts.Model
+	if model == nil {
+		model = DefaultScoringModel()
+	}
+	return !opts.PointInTime() &&
+		model.Name == e.Options.Model.Name &&
+		opts.Normalization == e.Options.Normalization &&
+		opts.minCoverage() == e.Options.minCoverage() &&
+		opts.DecayHalfLife == e.Options.DecayHalfLife
+}
+
+// start loads and scores every currency and skips notices already in the table.
+func (e *Engine) start(ctx context.Context) error {
+	if e.Snapshots.DB != nil {
+		var last int64
+		err := e.Snapshots.DB.NewSelect().
+			Model((*models.IngestNotice)(nil)).
+			ColumnExpr("COALESCE(MAX(id), 0)").
+			Scan(ctx, &last)
+		if err != nil {
+			return fmt.Errorf("scoring engine: select last notice: %w", err)
+		}
+		e.lastNotice = last
+	}
+
+	snapshots, err := e.Snapshots.Load(ctx, e.Options)
+	if err != nil {
+		return fmt.Errorf("scoring engine: load snapshots: %w", err)
+	}
+	scores, err := e.Snapshots.scoreSnapshots(ctx, snapshots, e.Options)
+	if err != nil {
+		return fmt.Errorf("scoring engine: score snapshots: %w", err)
+	}
+	instruments, err := e.Instruments.List(ctx)
+	if err != nil {
+		return fmt.Errorf("scoring engine: load instruments: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.snapshots = make(map[string]MacroSnapshot, len(snapshots))
+	for _, s := range snapshots {
+		e.snapshots[s.Country] = s
+	}
+	e.scores = scores
+	e.instruments = BuildInstrumentScores(scores, instruments)
+	e.pairs = pairScores(scores)
+	log.Printf("scoring engine: scored %d currencies and %d instruments (model %s)",
+		len(e.scores), len(e.instruments), e.Options.Model.Name)
+	return nil
+}
+
+// currencies lists the currencies the engine holds snapshots for.
+func (e *Engine) currencies() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	codes := make([]string, 0, len(e.snapshots))
+	for code := range e.snapshots {
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// pollNotices returns the currencies named by notices newer than the last one seen.
+func (e *Engine) pollNotices(ctx context.Context) ([]string, error) {
+	var notices []models.IngestNotice
+	err := e.Snapshots.DB.NewSelect().
+		Model(&notices).
+		Where("id > ?", e.lastNotice).
+		Order("id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	for _, n := range notices {
+		codes = append(codes, n.Currency)
+		e.lastNotice = n.ID
+	}
+	return codes, nil
+}
+
+func (e *Engine) rescoreAndLog(ctx context.Context, codes, trigger []string) {
+	changes, err := e.rescore(ctx, codes, trigger)
+	if err != nil {
+		log.Printf("scoring engine: rescore %v: %v", codes, err)
+		return
+	}
+	if len(changes) > 0 {
+		log.Printf("scoring engine: %d score changes after %v", len(changes), changes[0].TriggeredBy)
+	}
+}
+
+// rescore reloads the snapshots of the given currencies, rescores them and the
+// instruments and pairs depending on them, and records the scores that moved.
+// Cross-sectional normalisation ranks currencies against each other, so there
+// every currency is rescored. The changes name trigger as their cause, or the
+// given currencies when trigger is nil.
+func (e *Engine) rescore(ctx context.Context, codes, trigger []string) ([]ScoreChange, error) {
+	affected := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		affected[strings.ToUpper(strings.TrimSpace(code))] = true
+	}
+	if trigger == nil {
+		trigger = make([]string, 0, len(affected))
+		for code := range affected {
+			trigger = append(trigger, code)
+		}
+		sort.Strings(trigger)
+	}
+
+	loaded, err := e.Snapshots.Load(ctx, e.Options)
+	if err != nil {
+		return nil, fmt.Errorf("load snapshots: %w", err)
+	}
+	instruments, err := e.Instruments.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load instruments: %w", err)
+	}
+
+	e.mu.RLock()
+	snapshots := make(map[string]MacroSnapshot, len(e.snapshots))
+	for code, s := range e.snapshots {
+		snapshots[code] = s
+	}
+	e.mu.RUnlock()
+
+	var events []EngineEvent
+	for _, s := range loaded {
+		if !affected[s.Country] {
+			continue
+		}
+		if prev, ok := snapshots[s.Country]; ok {
+			for _, rel := range newReleases(prev, s) {
+				events = append(events, EngineEvent{Type: EventRelease, TS: time.Now().UTC(), Currencies: []string{s.Country}, Release: &rel})
+			}
+		}
+		snapshots[s.Country] = s
+	}
+	var batch []MacroSnapshot
+	for code, s := range snapshots {
+		if affected[code] || e.Options.Normalization == NormalizationCrossSection {
+			batch = append(batch, s)
+		}
+	}
+	sort.Slice(batch, func(i, j int) bool { return batch[i].Country < batch[j].Country })
+
+	rescored, err := e.Snapshots.scoreSnapshots(ctx, batch, e.Options)
+	if err != nil {
+		return nil, fmt.Errorf("score snapshots: %w", err)
+	}
+
+	e.mu.Lock()
+	before := e.scores
+	scores := make(map[string]ScoreBreakdown, len(before)+len(rescored))
+	for code, s := range before {
+		scores[code] = s
+	}
+	for code, s := range rescored {
+		scores[code] = s
+	}
+	for _, s := range batch {
+		snapshots[s.Country] = s
+	}
+
+	now := time.Now().UTC()
+	var changes []ScoreChange
+	record := func(c ScoreChange, prev *float64, after float64) {
+		if prev != nil && math.Abs(after-*prev) < e.MinDelta {
+			return
+		}
+		c.TS, c.Model, c.Before, c.After, c.TriggeredBy = now, e.Options.Model.Name, prev, after, trigger
+		if prev != nil {
+			c.Delta = round(after-*prev, 3)
+		}
+		changes = append(changes, c)
+	}
+
+	changed := make(map[string]bool, len(rescored))
+	for code, s := range rescored {
+		changed[code] = true
+		var prev *float64
+		if old, ok := before[code]; ok {
+			prev = &old.TotalScore
+		}
+		record(ScoreChange{Kind: ChangeCurrency, Subject: code}, prev, s.TotalScore)
+	}
+
+	// keep unaffected instrument scores, dropping instruments no longer in the catalogue
+	instScores := make(map[string]InstrumentScore, len(instruments))
+	for _, inst := range instruments {
+		if s, ok := e.instruments[inst.Symbol]; ok {
+			instScores[inst.Symbol] = s
+		}
+	}
+	for symbol, s := range BuildInstrumentScores(scores, dependentInstruments(instruments, changed)) {
+		var prev *float64
+		if old, ok := e.instruments[symbol]; ok {
+			prev = &old.TotalScore
+		}
+		record(ScoreChange{Kind: ChangeInstrument, Subject: symbol}, prev, s.TotalScore)
+		instScores[symbol] = s
+	}
+
+	pairs := pairScores(scores)
+	for pair, v := range pairs {
+		if !changed[pair.Base] && !changed[pair.Quote] {
+			continue
+		}
+		var prev *float64
+		if old, ok := e.pairs[pair]; ok {
+			prev = &old
+		}
+		record(ScoreChange{Kind: ChangePair, Subject: pair.Symbol(), Base: pair.Base, Quote: pair.Quote}, prev, v)
+	}
+
+	e.snapshots, e.scores, e.instruments, e.pairs = snapshots, scores, instScores, pairs
+	e.mu.Unlock()
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+		return changes[i].Subject < changes[j].Subject
+	})
+	e.publish(append(events, changeEvents(changes, instruments)...))
+	if err := e.saveChanges(ctx, changes); err != nil {
+		return changes, err
+	}
+	return changes, nil
+}
+
+// dependentInstruments returns the instruments driven by any changed currency;
+// a GLOBAL driver depends on every currency.
+func dependentInstruments(instruments []InstrumentDef, changed map[string]bool) []InstrumentDef {
+	var out []InstrumentDef
+	for _, inst := range instruments {
+		dependent := changed[inst.BaseFX] || changed[inst.QuoteFX]
+		for _, d := range inst.Drivers {
+			if d.Currency == GlobalDriver || changed[d.Currency] {
+				dependent = true
+			}
+		}
+		if dependent {
+			out = append(out, inst)
+		}
+	}
+	return out
+}
+
+// currencyPair is a base/quote pair of currency codes.
+type currencyPair struct {
+	Base, Quote string
+}
+
+// Symbol Symbol, e.g. "EURUSD"
+func (p currencyPair) Symbol() string { return p.Base + p.Quote }
+
+// pairScores scores every unordered currency pair once, with the codes in
+// alphabetical order, e.g. EUR/USD.
+func pairScores(scores map[string]ScoreBreakdown) map[currencyPair]float64 {
+	codes := make([]string, 0, len(scores))
+	for code := range scores {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	out := make(map[currencyPair]float64)
+	for i, base := range codes {
+		for _, quote := range codes[i+1:] {
+			out[currencyPair{Base: base, Quote: quote}] = round(scores[base].TotalScore-scores[quote].TotalScore, 3)
+		}
+	}
+	return out
+}
+
+func (e *Engine) saveChanges(ctx context.Context, changes []ScoreChange) error {
+	if e.Snapshots.DB == nil || len(changes) == 0 {
+		return nil
+	}
+	rows := make([]models.ScoreChangeEvent, len(changes))
+	for i, c := range changes {
+		rows[i] = models.ScoreChangeEvent{
+			TS:          c.TS,
+			Kind:        c.Kind,
+			Subject:     c.Subject,
+			Model:       c.Model,
+			Before:      c.Before,
+			After:       c.After,
+			Delta:       c.Delta,
+			TriggeredBy: strings.Join(c.TriggeredBy, ","),
+		}
+	}
+	if _, err := e.Snapshots.DB.NewInsert().Model(&rows).Exec(ctx); err != nil {
+		return fmt.Errorf("insert score changes: %w", err)
+	}
+	return nil
+}
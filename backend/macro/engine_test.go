@@ -0,0 +1,140 @@
+package macro
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEngineRescoreAllRebuildsCatalogue(t *testing.T) {
+	ctx := context.Background()
+	catalogue := DefaultInstruments
+	t.Cleanup(func() { DefaultInstruments = catalogue })
+	DefaultInstruments = []InstrumentDef{
+		{Symbol: "US500", AssetType: "index", BaseFX: "USD"},
+		{Symbol: "JP225", AssetType: "index", BaseFX: "JPY"},
+	}
+
+	e := NewEngine(NewSnapshotRepository(nil, "../data/macro.json"), NewInstrumentRepository(nil), ScoreOptions{})
+	if err := e.start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := e.InstrumentScores(ScoreOptions{})
+	if before["JP225"].TotalScore == before["US500"].TotalScore {
+		t.Fatal("fixture needs JPY and USD to score differently")
+	}
+
+	// JP225 now rides USD, US500 is removed and GER40 added
+	DefaultInstruments = []InstrumentDef{
+		{Symbol: "JP225", AssetType: "index", BaseFX: "USD"},
+		{Symbol: "GER40", AssetType: "index", BaseFX: "EUR"},
+	}
+	e.RescoreAll()
+	if _, err := e.rescore(ctx, <-e.notify, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	after, _ := e.InstrumentScores(ScoreOptions{})
+	if _, ok := after["US500"]; ok {
+		t.Error("removed instrument US500 is still scored")
+	}
+	if _, ok := after["GER40"]; !ok {
+		t.Error("added instrument GER40 is not scored")
+	}
+	if after["JP225"].TotalScore != before["US500"].TotalScore {
+		t.Errorf("JP225 = %v, want the USD-driven score %v", after["JP225"].TotalScore, before["US500"].TotalScore)
+	}
+}
+
+func TestEngineRunning(t *testing.T) {
+	e := NewEngine(NewSnapshotRepository(nil, "../data/macro.json"), NewInstrumentRepository(nil), ScoreOptions{})
+	if e.Running() {
+		t.Fatal("running before Start")
+	}
+	if err := e.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !e.Running() {
+		t.Fatal("not running after Start")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := e.Run(ctx); err != context.Canceled {
+		t.Fatalf("Run = %v, want context.Canceled", err)
+	}
+	if e.Running() {
+		t.Error("still running after Run returned")
+	}
+
+	failing := NewEngine(NewSnapshotRepository(nil, ""), NewInstrumentRepository(nil), ScoreOptions{})
+	if err := failing.Start(context.Background()); err == nil || failing.Running() {
+		t.Errorf("Start without data = %v, running %v; want an error and not running", err, failing.Running())
+	}
+}
+
+func TestPairChangesKeepTheirLegs(t *testing.T) {
+	scores := map[string]ScoreBreakdown{"USD": {TotalScore: 0.5}, "CNH": {TotalScore: 0.1}, "EURO": {TotalScore: -0.2}}
+	pairs := pairScores(scores)
+	want := map[currencyPair]float64{{"CNH", "EURO"}: 0.3, {"CNH", "USD"}: -0.4, {"EURO", "USD"}: -0.7}
+	if len(pairs) != len(want) {
+		t.Fatalf("pairs = %v, want %v", pairs, want)
+	}
+	for p, v := range want {
+		if got, ok := pairs[p]; !ok || math.Abs(got-v) > eps {
+			t.Errorf("%s = %v, want %v", p.Symbol(), got, v)
+		}
+	}
+
+	events := changeEvents([]ScoreChange{{Kind: ChangePair, Subject: "EUROUSD", Base: "EURO", Quote: "USD"}}, nil)
+	if ev := events[0]; ev.Symbol != "EUROUSD" || !slices.Equal(ev.Currencies, []string{"EURO", "USD"}) {
+		t.Fatalf("pair event = %+v, want currencies EURO and USD", ev)
+	}
+}
+
+func TestEngineChangeTriggers(t *testing.T) {
+	ctx := context.Background()
+	e := NewEngine(NewSnapshotRepository(nil, "../data/macro.json"), NewInstrumentRepository(nil), ScoreOptions{})
+	if err := e.start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// pretend USD scored differently before, so rescoring it records a move
+	nudge := func() {
+		e.mu.Lock()
+		s := e.scores["USD"]
+		s.TotalScore += 0.5
+		e.scores["USD"] = s
+		e.mu.Unlock()
+	}
+
+	for _, tt := range []struct {
+		name    string
+		codes   []string
+		trigger []string
+		want    []string
+	}{
+		{"new data", []string{"usd", "JPY"}, nil, []string{"JPY", "USD"}},
+		{"refresh", e.currencies(), []string{TriggerRefresh}, []string{TriggerRefresh}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			nudge()
+			changes, err := e.rescore(ctx, tt.codes, tt.trigger)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var usd *ScoreChange
+			for i, c := range changes {
+				if !slices.Equal(c.TriggeredBy, tt.want) {
+					t.Errorf("%s %s triggered by %v, want %v", c.Kind, c.Subject, c.TriggeredBy, tt.want)
+				}
+				if c.Kind == ChangeCurrency && c.Subject == "USD" {
+					usd = &changes[i]
+				}
+			}
+			if usd == nil || math.Abs(usd.Delta+0.5) > 1e-3 {
+				t.Fatalf("USD change = %+v, want a -0.5 move", usd)
+			}
+		})
+	}
+}
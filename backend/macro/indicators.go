@@ -0,0 +1,142 @@
+package macro
+
+import (
+	"sort"
+	"strings"
+)
+
+// Polarity says which direction of an indicator is supportive for the currency.
+const (
+	PolarityHigherIsBetter = 1
+	PolarityLowerIsBetter  = -1
+	PolarityTarget         = 0 // best near a target, e.g. inflation
+)
+
+// IndicatorDef is one entry of the canonical indicator taxonomy.
+type IndicatorDef struct {
+	Key       string   `json:"key"`   // internal key used by scoring models
+	Label     string   `json:"label"` // canonical category name, as stored by ingest
+	Unit      string   `json:"unit"`
+	Frequency string   `json:"frequency"`
+	Polarity  int      `json:"polarity"`
+	Aliases   []string `json:"aliases,omitempty"` // other provider category names
+	Derived   bool     `json:"derived,omitempty"` // computed from other releases, never ingested
+}
+
+// Indicators is the canonical indicator registry. LoadSnapshots, ingest and the
+// DB-backed snapshot builder all map provider categories through it.
+var Indicators = []IndicatorDef{
+	{Key: "gdp_growth_rate", Label: "GDP Growth Rate", Unit: "%", Frequency: "quarterly", Polarity: PolarityHigherIsBetter,
+		Aliases: []string{"GDP QoQ", "GDP Growth Rate QoQ", "Real GDP Growth"}},
+	{Key: "gdp_annual_growth_rate", Label: "GDP Annual Growth Rate", Unit: "%", Frequency: "quarterly", Polarity: PolarityHigherIsBetter,
+		Aliases: []string{"GDP YoY", "GDP Growth Rate YoY"}},
+	{Key: "unemployment_rate", Label: "Unemployment Rate", Unit: "%", Frequency: "monthly", Polarity: PolarityLowerIsBetter,
+		Aliases: []string{"Jobless Rate"}},
+	{Key: "inflation_rate", Label: "Inflation Rate", Unit: "%", Frequency: "monthly", Polarity: PolarityTarget,
+		Aliases: []string{"Inflation Rate YoY", "CPI YoY", "Consumer Price Index YoY"}},
+	{Key: "inflation_rate_mom", Label: "Inflation Rate MoM", Unit: "%", Frequency: "monthly", Polarity: PolarityTarget,
+		Aliases: []string{"CPI MoM"}},
+	{Key: "interest_rate", Label: "Interest Rate", Unit: "%", Frequency: "per meeting", Polarity: PolarityHigherIsBetter,
+		Aliases: []string{"Policy Rate", "Cash Rate", "Bank Rate"}},
+	{Key: "balance_of_trade", Label: "Balance of Trade", Unit: "local currency, millions", Frequency: "monthly", Polarity: PolarityHigherIsBetter,
+		Aliases: []string{"Trade Balance"}},
+	{Key: "current_account", Label: "Current Account", Unit: "local currency, millions", Frequency: "quarterly", Polarity: PolarityHigherIsBetter,
+		Aliases: []string{"Current Account Balance"}},
+	{Key: "business_confidence", Label: "Business Confidence", Unit: "index", Frequency: "monthly", Polarity: PolarityHigherIsBetter,
+		Aliases: []string{"Business Sentiment"}},
+	{Key: "manufacturing_pmi", Label: "Manufacturing PMI", Unit: "index", Frequency: "monthly", Polarity: PolarityHigherIsBetter,
+		Aliases: []string{"PMI Manufacturing"}},
+	{Key: "services_pmi", Label: "Services PMI", Unit: "index", Frequency: "monthly", Polarity: PolarityHigherIsBetter,
+		Aliases: []string{"PMI Services", "Non Manufacturing PMI"}},
+	{Key: "consumer_confidence", Label: "Consumer Confidence", Unit: "index", Frequency: "monthly", Polarity: PolarityHigherIsBetter,
+		Aliases: []string{"Consumer Sentiment"}},
+	{Key: "retail_sales_mom", Label: "Retail Sales MoM", Unit: "%", Frequency: "monthly", Polarity: PolarityHigherIsBetter,
+		Aliases: []string{"Retail Sales MoM SA"}},
+	{Key: RealRateIndicator, Label: "Real Interest Rate", Unit: "%", Frequency: "monthly", Polarity: PolarityHigherIsBetter,
+		Derived: true},
+	{Key: SurpriseIndicator, Label: "Economic Surprise Index", Unit: "z-score", Frequency: "daily", Polarity: PolarityHigherIsBetter,
+		Derived: true},
+	{Key: PolicyStanceIndicator, Label: "Policy Stance", Unit: "score", Frequency: "per meeting", Polarity: PolarityHigherIsBetter,
+		Derived: true},
+}
+
+// IndicatorKeys lists the keys accepted by MacroSnapshot.Indicator.
+var IndicatorKeys = indicatorKeys()
+
+// indicatorIndex maps every normalised key, label and alias to its registry entry.
+var indicatorIndex = buildIndicatorIndex()
+
+func indicatorKeys() []string {
+	keys := make([]string, len(Indicators))
+	for i, def := range Indicators {
+		keys[i] = def.Key
+	}
+	return keys
+}
+
+func buildIndicatorIndex() map[string]IndicatorDef {
+	index := make(map[string]IndicatorDef)
+	for _, def := range Indicators {
+		index[normalizeCategory(def.Key)] = def
+		index[normalizeCategory(def.Label)] = def
+		for _, alias := range def.Aliases {
+			index[normalizeCategory(alias)] = def
+		}
+	}
+	return index
+}
+
+// normalizeCategory lower-cases a category and collapses whitespace and
+// underscores, so "Inflation Rate MoM " and "inflation_rate_mom" compare equal.
+func normalizeCategory(category string) string {
+	category = strings.NewReplacer("_", " ", "-", " ").Replace(strings.ToLower(category))
+	return strings.Join(strings.Fields(category), " ")
+}
+
+// LookupIndicator finds the registry entry for a provider category name, key or alias.
+func LookupIndicator(category string) (IndicatorDef, bool) {
+	def, ok := indicatorIndex[normalizeCategory(category)]
+	return def, ok
+}
+
+// CanonicalCategory returns the registry label for category, or the trimmed
+// category itself when it is not in the registry.
+func CanonicalCategory(category string) (string, bool) {
+	if def, ok := LookupIndicator(category); ok {
+		return def.Label, true
+	}
+	return strings.TrimSpace(category), false
+}
+
+// UnmappedCategories counts category names that are not in the registry.
+type UnmappedCategories map[string]int
+
+// Add records category if it is unmapped and reports whether it was.
+func (u UnmappedCategories) Add(category string) bool {
+	if _, ok := LookupIndicator(category); ok {
+		return false
+	}
+	u[strings.TrimSpace(category)]++
+	return true
+}
+
+// CategoryCount is one unmapped category and how often it was seen.
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// Sorted lists the unmapped categories, most frequent first.
+func (u UnmappedCategories) Sorted() []CategoryCount {
+	out := make([]CategoryCount, 0, len(u))
+	for c, n := range u {
+		out = append(out, CategoryCount{Category: c, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Category < out[j].Category
+	})
+	return out
+}
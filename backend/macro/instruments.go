@@ -8,73 +8,123 @@ import (
 // InstrumentScore InstrumentScore
 type InstrumentScore struct {
 	Symbol      string             `json:"symbol"`
-	AssetType   string             `json:"asset_type"` // "index" or "metal"
+	AssetType   string             `json:"asset_type"` // one of AssetTypes
 	TotalScore  float64            `json:"total_score"`
 	Components  map[string]float64 `json:"components"`
 	Explanation string             `json:"explanation"`
 }
 
-// Hard-coded mapping for now; later you can extend this list.
-var instrumentBase = []struct {
-	Symbol    string
-	AssetType string
-	BaseFX    string // which macro country code/currency drives it
-}{
-	{"US500", "index", "USD"},
-	{"US100", "index", "USD"},
-	{"JP225", "index", "JPY"}, // Nikkei 225
-	{"XAUUSD", "metal", "USD"},
-	{"XAGUSD", "metal", "USD"},
-}
-
 // BuildInstrumentScores derives instrument scores from currency macro scores.
-func BuildInstrumentScores(scoresByCountry map[string]ScoreBreakdown) map[string]InstrumentScore {
+// Instruments whose driving currency has no score are skipped.
+func BuildInstrumentScores(scoresByCountry map[string]ScoreBreakdown, instruments []InstrumentDef) map[string]InstrumentScore {
 	out := make(map[string]InstrumentScore)
 
-	for _, inst := range instrumentBase {
+	for _, inst := range instruments {
 		baseScore, ok := scoresByCountry[inst.BaseFX]
 		if !ok {
 			continue
 		}
-		instScore := scoreInstrument(inst.Symbol, inst.AssetType, baseScore)
+		if inst.AssetType == "fx" {
+			quoteScore, ok := scoresByCountry[inst.QuoteFX]
+			if !ok {
+				continue
+			}
+			out[inst.Symbol] = scoreFXInstrument(inst, baseScore, quoteScore, scoresByCountry)
+			continue
+		}
+		instScore := scoreInstrument(inst, baseScore, scoresByCountry)
 		out[inst.Symbol] = instScore
 	}
 
 	return out
 }
 
-// scoring rules per asset type
-func scoreInstrument(symbol, assetType string, base ScoreBreakdown) InstrumentScore {
+// scoring rules per asset type, blended with the instrument's extra drivers
+func scoreInstrument(inst InstrumentDef, base ScoreBreakdown, scoresByCountry map[string]ScoreBreakdown) InstrumentScore {
+	symbol, assetType := inst.Symbol, inst.AssetType
 	comps := make(map[string]float64)
 
-	// pull some key components for readability
-	gdp := base.Components["gdp_growth"]
-	unemp := base.Components["unemployment"]
-	infl := base.Components["inflation"]
-	rate := base.Components["interest_rate"]
-	conf := base.Components["business_confidence"]
-	manu := base.Components["manufacturing_pmi"]
-	svc := base.Components["services_pmi"]
+	// pull some key components for readability; components without data are
+	// absent from base.Components and the themes built on them are omitted
+	gdp, hasGDP := base.Components["gdp_growth"]
+	unemp, hasUnemp := base.Components["unemployment"]
+	infl, hasInfl := base.Components["inflation"]
+	rate, hasRate := base.Components["interest_rate"]
+	manu, hasManu := base.Components["manufacturing_pmi"]
 
 	switch assetType {
 	case "index":
 		// Risk assets like US500/US100 like:
 		// + GDP growth, + confidence, + PMIs
 		// - too high rates, - very high inflation
-		comps["growth"] = gdp
-		comps["confidence"] = avgNonZero(conf, manu, svc)
-		comps["employment"] = unemp
-		comps["rates_headwind"] = -rate
-		comps["inflation_headwind"] = -math.Abs(infl)
+		if hasGDP {
+			comps["growth"] = gdp
+		}
+		if v, ok := avgPresent(base.Components, "business_confidence", "manufacturing_pmi", "services_pmi"); ok {
+			comps["confidence"] = v
+		}
+		if hasUnemp {
+			comps["employment"] = unemp
+		}
+		if hasRate {
+			comps["rates_headwind"] = -rate
+		}
+		if hasInfl {
+			comps["inflation_headwind"] = -math.Abs(infl)
+		}
 
 	case "metal":
 		// Metals like gold/silver tend to like:
 		// + inflation (especially above target)
 		// + lower real rates / dovish policy
 		// + weaker currency (we invert the base macro score)
-		comps["inflation_theme"] = math.Max(0, infl)   // only + if inflation above target
-		comps["rates_theme"] = -rate                   // lower rates = positive
+		if hasInfl {
+			comps["inflation_theme"] = math.Max(0, infl) // only + if inflation above target
+		}
+		if hasRate {
+			comps["rates_theme"] = -rate // lower rates = positive
+		}
 		comps["usd_weakness_theme"] = -base.TotalScore // weaker USD boosts XAUUSD/XAGUSD
+
+	case "energy":
+		// Oil (USOIL/UKOIL) trades on demand:
+		// + manufacturing activity, + services/business activity, + GDP growth
+		if hasManu {
+			comps["industrial_demand"] = manu
+		}
+		if v, ok := avgPresent(base.Components, "services_pmi", "business_confidence"); ok {
+			comps["activity"] = v
+		}
+		if hasGDP {
+			comps["growth"] = gdp
+		}
+
+	case "bond":
+		// Bond futures (US10Y/DE10Y) rise when yields fall:
+		// + inflation at or below target, - hawkish rates, - hot growth
+		if hasInfl {
+			comps["inflation_theme"] = infl // component is positive when inflation is contained
+		}
+		if hasRate {
+			comps["rates_theme"] = -rate
+		}
+		if hasGDP {
+			comps["growth_theme"] = -gdp
+		}
+
+	case "crypto":
+		// Crypto (BTCUSD) trades on liquidity:
+		// + low or negative real rates, + easing policy, + risk appetite
+		if realRate, ok := base.RawIndicators.Indicator(RealRateIndicator); ok {
+			comps["real_rate_theme"] = clamp(-realRate/3, -1, 1)
+		}
+		if hasRate {
+			comps["liquidity_theme"] = -rate
+		}
+		if v, ok := avgPresent(base.Components, "business_confidence", "consumer_confidence"); ok {
+			comps["risk_appetite"] = v
+		}
+
 	default:
 		// fallback: just mirror base macro score
 		comps["macro"] = base.TotalScore
@@ -91,10 +141,14 @@ func scoreInstrument(symbol, assetType string, base ScoreBreakdown) InstrumentSc
 	if count > 0 {
 		total = sum / count
 	}
+
+	total, contributions := blendDrivers(comps, total, inst.Drivers, scoresByCountry)
+
 	total = round(total, 3)
 	comps = roundMap(comps, 3)
 
 	explanation := explainInstrument(symbol, assetType, base, comps, total)
+	explanation += explainDrivers(inst.Drivers, roundMap(contributions, 3))
 
 	return InstrumentScore{
 		Symbol:      symbol,
@@ -105,44 +159,108 @@ func scoreInstrument(symbol, assetType string, base ScoreBreakdown) InstrumentSc
 	}
 }
 
-func avgNonZero(vals ...float64) float64 {
+// scoreFXInstrument scores a currency pair as base minus quote, the same
+// difference PairSentimentFromSnapshots uses, with per-component differentials.
+func scoreFXInstrument(inst InstrumentDef, base, quote ScoreBreakdown, scoresByCountry map[string]ScoreBreakdown) InstrumentScore {
+	comps := make(map[string]float64)
+	for k, bv := range base.Components {
+		if qv, ok := quote.Components[k]; ok {
+			comps[k] = bv - qv
+		}
+	}
+
+	carry, realDiff, rateComps := rateDifferentials(base, quote)
+	for k, v := range rateComps {
+		comps[k] = v
+	}
+
+	pairScore := base.TotalScore - quote.TotalScore
+	total, contributions := blendDrivers(comps, pairScore, inst.Drivers, scoresByCountry)
+
+	total = round(total, 3)
+	comps = roundMap(comps, 3)
+
+	explanation := fmt.Sprintf("%s currently has a %s (score %.2f). ", inst.Symbol, instrumentBias(total), total)
+	explanation += explainPair(base, quote, round(pairScore, 3))
+	explanation += explainCarry(base.Country, quote.Country, carry, realDiff)
+	explanation += explainDrivers(inst.Drivers, roundMap(contributions, 3))
+
+	return InstrumentScore{
+		Symbol:      inst.Symbol,
+		AssetType:   inst.AssetType,
+		TotalScore:  total,
+		Components:  comps,
+		Explanation: explanation,
+	}
+}
+
+// blendDrivers mixes driver contributions into total: the asset-class score counts
+// with weight 1, each driver with |weight|. Contributions are added to comps.
+func blendDrivers(
+	comps map[string]float64,
+	total float64,
+	drivers []InstrumentDriver,
+	scoresByCountry map[string]ScoreBreakdown,
+) (float64, map[string]float64) {
+	contributions, weightSum := driverContributions(drivers, scoresByCountry)
+	if weightSum == 0 {
+		return total, contributions
+	}
+
+	blended := total
+	for k, c := range contributions {
+		comps[k] = c
+		blended += c
+	}
+	return blended / (1 + weightSum), contributions
+}
+
+// avgPresent averages the components named by keys that have data.
+func avgPresent(components map[string]float64, keys ...string) (float64, bool) {
 	var sum float64
 	var count float64
-	for _, v := range vals {
-		if v != 0 {
+	for _, k := range keys {
+		if v, ok := components[k]; ok {
 			sum += v
 			count++
 		}
 	}
 	if count == 0 {
-		return 0
+		return 0, false
 	}
-	return sum / count
+	return sum / count, true
 }
 
-func explainInstrument(symbol, assetType string, base ScoreBreakdown, comps map[string]float64, total float64) string {
-	var bias string
+func instrumentBias(total float64) string {
 	switch {
 	case total > 0.3:
-		bias = "strong bullish bias"
+		return "strong bullish bias"
 	case total > 0.1:
-		bias = "mild bullish bias"
+		return "mild bullish bias"
 	case total < -0.3:
-		bias = "strong bearish bias"
+		return "strong bearish bias"
 	case total < -0.1:
-		bias = "mild bearish bias"
+		return "mild bearish bias"
 	default:
-		bias = "roughly neutral stance"
+		return "roughly neutral stance"
 	}
+}
 
+func explainInstrument(symbol, assetType string, base ScoreBreakdown, comps map[string]float64, total float64) string {
 	text := fmt.Sprintf("%s currently has a %s (score %.2f) based on %s macro conditions.",
-		symbol, bias, total, base.Country)
+		symbol, instrumentBias(total), total, base.Country)
 
 	switch assetType {
 	case "index":
 		text += explainIndexFromComponents(base, comps)
 	case "metal":
 		text += explainMetalFromComponents(base, comps)
+	case "energy":
+		text += explainEnergyFromComponents(base, comps)
+	case "bond":
+		text += explainBondFromComponents(base, comps)
+	case "crypto":
+		text += explainCryptoFromComponents(base, comps)
 	}
 
 	return text
@@ -205,3 +323,102 @@ func explainMetalFromComponents(base ScoreBreakdown, comps map[string]float64) s
 	}
 	return text
 }
+
+func explainEnergyFromComponents(base ScoreBreakdown, comps map[string]float64) string {
+	var supports []string
+	var headwinds []string
+
+	if comps["industrial_demand"] > 0.15 {
+		supports = append(supports, "expanding manufacturing")
+	} else if comps["industrial_demand"] < -0.15 {
+		headwinds = append(headwinds, "contracting manufacturing")
+	}
+	if comps["activity"] > 0.15 {
+		supports = append(supports, "firm services and business activity")
+	} else if comps["activity"] < -0.15 {
+		headwinds = append(headwinds, "soft services and business activity")
+	}
+	if comps["growth"] > 0.15 {
+		supports = append(supports, "solid GDP growth")
+	} else if comps["growth"] < -0.15 {
+		headwinds = append(headwinds, "weak GDP growth")
+	}
+
+	text := ""
+	if len(supports) > 0 {
+		text += " Demand is supported by " + joinWithAnd(supports) + "."
+	}
+	if len(headwinds) > 0 {
+		text += " Demand is held back by " + joinWithAnd(headwinds) + "."
+	}
+	if text == "" {
+		text = " Demand signals for energy are mixed."
+	}
+	return text
+}
+
+func explainBondFromComponents(base ScoreBreakdown, comps map[string]float64) string {
+	var supports []string
+	var headwinds []string
+
+	if comps["inflation_theme"] > 0.15 {
+		supports = append(supports, "contained inflation")
+	} else if comps["inflation_theme"] < -0.15 {
+		headwinds = append(headwinds, "inflation running away from target")
+	}
+	if comps["rates_theme"] > 0.15 {
+		supports = append(supports, "room for lower policy rates")
+	} else if comps["rates_theme"] < -0.15 {
+		headwinds = append(headwinds, "restrictive policy rates")
+	}
+	if comps["growth_theme"] > 0.15 {
+		supports = append(supports, "slowing growth")
+	} else if comps["growth_theme"] < -0.15 {
+		headwinds = append(headwinds, "strong growth")
+	}
+
+	text := ""
+	if len(supports) > 0 {
+		text += " Prices are supported by " + joinWithAnd(supports) + "."
+	}
+	if len(headwinds) > 0 {
+		text += " Yields are pushed higher by " + joinWithAnd(headwinds) + "."
+	}
+	if text == "" {
+		text = " Rate and inflation signals for this bond are balanced."
+	}
+	return text
+}
+
+func explainCryptoFromComponents(base ScoreBreakdown, comps map[string]float64) string {
+	var supports []string
+	var headwinds []string
+
+	if comps["real_rate_theme"] > 0.15 {
+		supports = append(supports, "low real interest rates")
+	} else if comps["real_rate_theme"] < -0.15 {
+		headwinds = append(headwinds, "high real interest rates")
+	}
+	if comps["liquidity_theme"] > 0.15 {
+		supports = append(supports, "easy monetary conditions")
+	} else if comps["liquidity_theme"] < -0.15 {
+		headwinds = append(headwinds, "tight monetary conditions")
+	}
+	if comps["risk_appetite"] > 0.15 {
+		supports = append(supports, "healthy confidence")
+	} else if comps["risk_appetite"] < -0.15 {
+		headwinds = append(headwinds, "weak confidence")
+	}
+
+	text := ""
+	if len(supports) > 0 {
+		text += " Liquidity tailwinds include " + joinWithAnd(supports) + "."
+	}
+	if len(headwinds) > 0 {
+		text += " Liquidity headwinds include " + joinWithAnd(headwinds) + "."
+	}
+	if text == "" {
+		text = " Liquidity signals for crypto are mixed."
+	}
+	return text
+}
@@ -0,0 +1,52 @@
+package macro
+
+import (
+	"maps"
+	"math"
+	"testing"
+)
+
+func TestBlendDrivers(t *testing.T) {
+	scores := map[string]ScoreBreakdown{
+		"USD": {Country: "USD", TotalScore: 0.6, Components: map[string]float64{"inflation": -0.4}},
+		"EUR": {Country: "EUR", TotalScore: -0.2, Components: map[string]float64{}},
+	}
+
+	tests := []struct {
+		name    string
+		drivers []InstrumentDriver
+		want    float64
+		contrib map[string]float64
+	}{
+		{"no drivers", nil, 0.5, map[string]float64{}},
+		{"unscored currency", []InstrumentDriver{{Currency: "GBP", Weight: 1}}, 0.5, map[string]float64{}},
+		{"missing component", []InstrumentDriver{{Currency: "USD", Component: "growth", Weight: 1}}, 0.5, map[string]float64{}},
+		{"one driver", []InstrumentDriver{{Currency: "USD", Weight: 1}}, (0.5 + 0.6) / 2, map[string]float64{"driver_usd": 0.6}},
+		{"inverse leg", []InstrumentDriver{{Currency: "USD", Weight: -1}}, (0.5 - 0.6) / 2, map[string]float64{"driver_usd": -0.6}},
+		{"component driver", []InstrumentDriver{{Currency: "USD", Component: "inflation", Weight: 0.5}}, (0.5 - 0.2) / 1.5, map[string]float64{"driver_usd_inflation": -0.2}},
+		{"global average", []InstrumentDriver{{Currency: GlobalDriver, Weight: 1}}, (0.5 + 0.2) / 2, map[string]float64{"driver_global": 0.2}},
+		{"weights by magnitude", []InstrumentDriver{{Currency: "USD", Weight: 1}, {Currency: "EUR", Weight: -0.5}}, (0.5 + 0.6 + 0.1) / 2.5,
+			map[string]float64{"driver_usd": 0.6, "driver_eur": 0.1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comps := map[string]float64{"growth": 0.5}
+			total, contrib := blendDrivers(comps, 0.5, tt.drivers, scores)
+
+			if math.Abs(total-tt.want) > eps {
+				t.Errorf("total = %v, want %v", total, tt.want)
+			}
+			if !maps.EqualFunc(contrib, tt.contrib, func(a, b float64) bool { return math.Abs(a-b) < eps }) {
+				t.Errorf("contributions = %v, want %v", contrib, tt.contrib)
+			}
+			for k, v := range tt.contrib {
+				if comps[k] != contrib[k] {
+					t.Errorf("components[%s] = %v, want the contribution %v", k, comps[k], v)
+				}
+			}
+			if comps["growth"] != 0.5 || len(comps) != 1+len(tt.contrib) {
+				t.Errorf("components = %v", comps)
+			}
+		})
+	}
+}
@@ -3,55 +3,160 @@ package macro
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
-// MacroSnapshot struct
+// MacroSnapshot holds the latest reading of each indicator for one currency.
+// A nil field means the indicator has no data, which is different from a zero reading.
 type MacroSnapshot struct {
-	Country             string  `json:"Country"`
-	GDPGrowthRate       float64 `json:"GDP Growth Rate"`
-	UnemploymentRate    float64 `json:"Unemployment Rate"`
-	InflationRate       float64 `json:"Inflation Rate"`
-	InterestRate        float64 `json:"Interest Rate"`
-	InflationRateMoM    float64 `json:"Inflation Rate MoM "`
-	BalanceOfTrade      float64 `json:"Balance of Trade "`
-	CurrentAccount      float64 `json:"Current Account"`
-	BusinessConfidence  float64 `json:"Business Confidence "`
-	ManufacturingPMI    float64 `json:"Manufacturing PMI"`
-	ServicesPMIRaw      any     `json:"Services PMI"` // can be "" or number
-	ConsumerConfidence  float64 `json:"Consumer Confidence "`
-	RetailSalesMoM      float64 `json:"Retail Sales MoM "`
-	GDPAnnualGrowthRate float64 `json:"GDP Annual Growth Rate"`
+	Country             string   `json:"Country"`
+	GDPGrowthRate       *float64 `json:"GDP Growth Rate"`
+	UnemploymentRate    *float64 `json:"Unemployment Rate"`
+	InflationRate       *float64 `json:"Inflation Rate"`
+	InterestRate        *float64 `json:"Interest Rate"`
+	InflationRateMoM    *float64 `json:"Inflation Rate MoM"`
+	BalanceOfTrade      *float64 `json:"Balance of Trade"`
+	CurrentAccount      *float64 `json:"Current Account"`
+	BusinessConfidence  *float64 `json:"Business Confidence"`
+	ManufacturingPMI    *float64 `json:"Manufacturing PMI"`
+	ServicesPMI         *float64 `json:"Services PMI"`
+	ConsumerConfidence  *float64 `json:"Consumer Confidence"`
+	RetailSalesMoM      *float64 `json:"Retail Sales MoM"`
+	GDPAnnualGrowthRate *float64 `json:"GDP Annual Growth Rate"`
+	RealRate            *float64 `json:"Real Interest Rate"`      // derived, see RealRateIndicator
+	SurpriseIndex       *float64 `json:"Economic Surprise Index"` // derived, see SurpriseIndicator
+	PolicyStance        *float64 `json:"Policy Stance"`           // derived, see PolicyStanceIndicator
+
+	// ReleasedAt holds the release time of each indicator key, when known.
+	ReleasedAt map[string]time.Time `json:"released_at,omitempty"`
 }
 
-// ParsedServicesPMI tries to read Services PMI even if it's "" in JSON.
-func (m MacroSnapshot) ParsedServicesPMI() *float64 {
-	switch v := m.ServicesPMIRaw.(type) {
-	case float64:
-		return &v
-	case string:
-		if v == "" {
-			return nil
-		}
-		if f, err := strconv.ParseFloat(v, 64); err == nil {
-			return &f
-		}
+// Indicator returns the value stored under an indicator key; ok is false
+// for unknown keys and for values the snapshot does not carry.
+func (m MacroSnapshot) Indicator(key string) (float64, bool) {
+	p := m.field(key)
+	if p == nil || *p == nil {
+		return 0, false
+	}
+	return **p, true
+}
+
+// field returns the snapshot field holding an indicator key, or nil for unknown keys.
+func (m *MacroSnapshot) field(key string) **float64 {
+	switch key {
+	case "gdp_growth_rate":
+		return &m.GDPGrowthRate
+	case "gdp_annual_growth_rate":
+		return &m.GDPAnnualGrowthRate
+	case "unemployment_rate":
+		return &m.UnemploymentRate
+	case "inflation_rate":
+		return &m.InflationRate
+	case "inflation_rate_mom":
+		return &m.InflationRateMoM
+	case "interest_rate":
+		return &m.InterestRate
+	case "balance_of_trade":
+		return &m.BalanceOfTrade
+	case "current_account":
+		return &m.CurrentAccount
+	case "business_confidence":
+		return &m.BusinessConfidence
+	case "manufacturing_pmi":
+		return &m.ManufacturingPMI
+	case "services_pmi":
+		return &m.ServicesPMI
+	case "consumer_confidence":
+		return &m.ConsumerConfidence
+	case "retail_sales_mom":
+		return &m.RetailSalesMoM
+	case RealRateIndicator:
+		return &m.RealRate
+	case SurpriseIndicator:
+		return &m.SurpriseIndex
+	case PolicyStanceIndicator:
+		return &m.PolicyStance
 	}
 	return nil
 }
 
-// LoadSnapshots func
+// setIndicator stores v under an indicator key; it reports false for unknown keys.
+func (m *MacroSnapshot) setIndicator(key string, v float64) bool {
+	p := m.field(key)
+	if p == nil {
+		return false
+	}
+	*p = &v
+	return true
+}
+
+// setRelease records when the indicator stored under key was released.
+func (m *MacroSnapshot) setRelease(key string, t time.Time) {
+	if m.ReleasedAt == nil {
+		m.ReleasedAt = make(map[string]time.Time)
+	}
+	m.ReleasedAt[key] = t.UTC()
+}
+
+// LoadSnapshots reads a JSON snapshot file. Category names are mapped through
+// the indicator registry, so stray whitespace or provider aliases in the keys
+// still land on the right field; unmapped categories are logged.
 func LoadSnapshots(path string) ([]MacroSnapshot, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read macro file: %w", err)
 	}
 
-	var snapshots []MacroSnapshot
-	if err := json.Unmarshal(data, &snapshots); err != nil {
+	var entries []map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
 		return nil, fmt.Errorf("unmarshal macro file: %w", err)
 	}
 
+	unmapped := make(UnmappedCategories)
+	snapshots := make([]MacroSnapshot, 0, len(entries))
+	for i, entry := range entries {
+		var snap MacroSnapshot
+		for name, raw := range entry {
+			if strings.EqualFold(strings.TrimSpace(name), "country") {
+				code, _ := raw.(string)
+				snap.Country = strings.ToUpper(strings.TrimSpace(code))
+				continue
+			}
+			def, ok := LookupIndicator(name)
+			if !ok {
+				unmapped.Add(name)
+				continue
+			}
+			if v, ok := snapshotValue(raw); ok {
+				snap.setIndicator(def.Key, v)
+			}
+		}
+		if snap.Country == "" {
+			return nil, fmt.Errorf("macro file entry %d has no Country", i)
+		}
+		snap.deriveRealRate()
+		snapshots = append(snapshots, snap)
+	}
+
+	if len(unmapped) > 0 {
+		log.Printf("macro file %s: unmapped categories %v", path, unmapped.Sorted())
+	}
+
 	return snapshots, nil
 }
+
+// snapshotValue reads a number that may be encoded as a string; "" and null mean no value.
+func snapshotValue(raw any) (float64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
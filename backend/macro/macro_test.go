@@ -0,0 +1,50 @@
+package macro
+
+import (
+	"bytes"
+	"log"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLoadSnapshotsMapsCategories(t *testing.T) {
+	var logged bytes.Buffer
+	out := log.Writer()
+	log.SetOutput(&logged)
+	snapshots, err := LoadSnapshots("testdata/snapshots.json")
+	log.SetOutput(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Country != "USD" || snapshots[1].Country != "JPY" {
+		t.Fatalf("snapshots = %+v, want USD and JPY", snapshots)
+	}
+
+	usd, jpy := snapshots[0], snapshots[1]
+	for _, f := range []struct {
+		name string
+		got  *float64
+		want float64
+	}{
+		{"USD InflationRateMoM", usd.InflationRateMoM, 0.3},
+		{"USD ConsumerConfidence", usd.ConsumerConfidence, 68.2},
+		{"USD RetailSalesMoM", usd.RetailSalesMoM, -0.2},
+		{"USD RealRate", usd.RealRate, 5.5 - 3.3},
+		{"JPY RetailSalesMoM", jpy.RetailSalesMoM, 1.7},
+	} {
+		if f.got == nil || math.Abs(*f.got-f.want) > eps {
+			t.Errorf("%s = %v, want %v", f.name, f.got, f.want)
+		}
+	}
+	if jpy.InterestRate != nil || jpy.RealRate != nil {
+		t.Errorf("JPY null interest rate = %v, real rate %v; want both unset", jpy.InterestRate, jpy.RealRate)
+	}
+
+	report := logged.String()
+	for _, want := range []string{"unmapped categories", "{Housing Starts 2}", "{Building Permits 1}"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("log %q does not report %q", report, want)
+		}
+	}
+}
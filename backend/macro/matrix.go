@@ -0,0 +1,68 @@
+package macro
+
+import (
+	"math"
+	"sort"
+)
+
+// PairRank is one currency pair oriented so that Base is the stronger side.
+type PairRank struct {
+	Pair        string  `json:"pair"`
+	Base        string  `json:"base"`
+	Quote       string  `json:"quote"`
+	PairScore   float64 `json:"pair_score"`
+	Explanation string  `json:"explanation"`
+}
+
+// PairMatrix holds every base/quote pair score plus the pairs ranked by divergence.
+type PairMatrix struct {
+	Currencies []string                      `json:"currencies"`
+	Matrix     map[string]map[string]float64 `json:"matrix"` // base → quote → pair score
+	Ranked     []PairRank                    `json:"ranked"`
+}
+
+// BuildPairMatrix computes the full N×N pair-score matrix from currency scores.
+// Ranked lists each pair once, strongest divergence first, keeping only pairs
+// whose absolute score is at least minAbs.
+func BuildPairMatrix(scores map[string]ScoreBreakdown, minAbs float64) PairMatrix {
+	codes := make([]string, 0, len(scores))
+	for code := range scores {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	matrix := make(map[string]map[string]float64, len(codes))
+	var ranked []PairRank
+
+	for i, base := range codes {
+		matrix[base] = make(map[string]float64, len(codes))
+		for j, quote := range codes {
+			pairScore := round(scores[base].TotalScore-scores[quote].TotalScore, 3)
+			matrix[base][quote] = pairScore
+
+			// rank each unordered pair once, from the stronger side
+			if j <= i || math.Abs(pairScore) < minAbs || pairScore == 0 {
+				continue
+			}
+			strong, weak := scores[base], scores[quote]
+			if pairScore < 0 {
+				strong, weak, pairScore = weak, strong, -pairScore
+			}
+			ranked = append(ranked, PairRank{
+				Pair:        strong.Country + weak.Country,
+				Base:        strong.Country,
+				Quote:       weak.Country,
+				PairScore:   pairScore,
+				Explanation: explainPair(strong, weak, pairScore),
+			})
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].PairScore > ranked[j].PairScore })
+
+	return PairMatrix{
+		Currencies: codes,
+		Matrix:     matrix,
+		Ranked:     ranked,
+	}
+}
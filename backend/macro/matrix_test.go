@@ -0,0 +1,65 @@
+package macro
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestBuildPairMatrix(t *testing.T) {
+	scores := map[string]ScoreBreakdown{
+		"USD": {Country: "USD", TotalScore: 0.5},
+		"EUR": {Country: "EUR", TotalScore: 0.2},
+		"GBP": {Country: "GBP", TotalScore: 0.2},
+		"JPY": {Country: "JPY", TotalScore: -0.3},
+	}
+
+	m := BuildPairMatrix(scores, 0)
+	if !slices.Equal(m.Currencies, []string{"EUR", "GBP", "JPY", "USD"}) {
+		t.Fatalf("currencies = %v", m.Currencies)
+	}
+	for _, c := range []struct {
+		base, quote string
+		want        float64
+	}{
+		{"USD", "JPY", 0.8},
+		{"JPY", "USD", -0.8},
+		{"EUR", "USD", -0.3},
+		{"EUR", "GBP", 0},
+		{"USD", "USD", 0},
+	} {
+		if got := m.Matrix[c.base][c.quote]; math.Abs(got-c.want) > eps {
+			t.Errorf("matrix[%s][%s] = %v, want %v", c.base, c.quote, got, c.want)
+		}
+	}
+
+	// each pair once from the stronger side, strongest first; ties keep
+	// alphabetical order and equal scores are left out
+	type ranked struct {
+		pair  string
+		score float64
+	}
+	want := []ranked{{"USDJPY", 0.8}, {"EURJPY", 0.5}, {"GBPJPY", 0.5}, {"USDEUR", 0.3}, {"USDGBP", 0.3}}
+	var got []ranked
+	for _, r := range m.Ranked {
+		if r.Pair != r.Base+r.Quote || r.Explanation == "" {
+			t.Errorf("rank %+v", r)
+		}
+		got = append(got, ranked{r.Pair, math.Round(r.PairScore*1000) / 1000})
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ranked = %v, want %v", got, want)
+	}
+
+	filtered := BuildPairMatrix(scores, 0.5)
+	var pairs []string
+	for _, r := range filtered.Ranked {
+		pairs = append(pairs, r.Pair)
+	}
+	if !slices.Equal(pairs, []string{"USDJPY", "EURJPY", "GBPJPY"}) {
+		t.Errorf("ranked with minAbs 0.5 = %v", pairs)
+	}
+	if len(filtered.Matrix["EUR"]) != 4 {
+		t.Errorf("minAbs must not filter the matrix itself")
+	}
+}
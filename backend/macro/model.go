@@ -0,0 +1,428 @@
+package macro
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Transforms understood by IndicatorRule.
+const (
+	TransformLinear          = "linear"           // (v - center) / scale
+	TransformBand            = "band"             // 0 inside [low, high], signed distance / scale outside
+	TransformTargetDeviation = "target_deviation" // -|v - center| / scale, 0 at target
+	TransformPMI             = "pmi"              // linear with center 50 and scale 10 by default
+	// momentum transforms read the release history instead of the latest level;
+	// anchored rules score movement towards the anchor as positive
+	TransformChange = "change" // (latest - release Lookback back) / scale
+	TransformTrend  = "trend"  // least-squares slope per release over the last Lookback releases / scale
+)
+
+// DefaultModelName is the model used when a request does not name one.
+const DefaultModelName = "default"
+
+// IndicatorRule turns one snapshot indicator into a score component.
+// The transformed value is multiplied by Sign and clamped to [Min, Max].
+type IndicatorRule struct {
+	Component string  `json:"component"` // key in ScoreBreakdown.Components
+	Indicator string  `json:"indicator"` // one of IndicatorKeys
+	Transform string  `json:"transform"`
+	Anchor    string  `json:"anchor,omitempty"` // take center/band from CountryParams, see Anchor*
+	Center    float64 `json:"center,omitempty"` // linear/pmi neutral value, target_deviation target
+	Low       float64 `json:"low,omitempty"`    // band lower edge
+	High      float64 `json:"high,omitempty"`   // band upper edge
+	Scale     float64 `json:"scale,omitempty"`  // distance that maps to ±1
+	Min       float64 `json:"min,omitempty"`    // saturation bounds, default -1..1
+	Max       float64 `json:"max,omitempty"`
+	Weight    float64 `json:"weight,omitempty"`   // default 1
+	Sign      float64 `json:"sign,omitempty"`     // +1 (default) or -1
+	Lookback  int     `json:"lookback,omitempty"` // change/trend: releases to look back over
+}
+
+// ScoringModel is a named set of indicator rules; the total score is the
+// weighted average of the components.
+type ScoringModel struct {
+	Name          string                   `json:"name"`
+	Description   string                   `json:"description,omitempty"`
+	Extends       string                   `json:"extends,omitempty"` // model whose rules and country params this one starts from
+	Rules         []IndicatorRule          `json:"rules"`
+	CountryParams map[string]CountryParams `json:"country_params,omitempty"` // overrides DefaultCountryParams
+}
+
+// DefaultScoringModel scores inflation, unemployment and rates against each
+// currency's own parameters; center values are used for currencies without any.
+func DefaultScoringModel() *ScoringModel {
+	m := &ScoringModel{
+		Name:        DefaultModelName,
+		Description: "Country-anchored levels and central-bank stance with equal weights plus lighter momentum components",
+		Rules: []IndicatorRule{
+			{Component: "gdp_growth", Indicator: "gdp_annual_growth_rate", Transform: TransformLinear, Scale: 4},                                                // -4%→-1, 0→0, 4%→1
+			{Component: "unemployment", Indicator: "unemployment_rate", Transform: TransformLinear, Anchor: AnchorNAIRU, Center: 10, Scale: 3, Sign: -1},        // 3pp below NAIRU→1
+			{Component: "inflation", Indicator: "inflation_rate", Transform: TransformBand, Anchor: AnchorInflationTarget, Low: 2, High: 2, Scale: 6, Sign: -1}, // ±6% outside target band → [-1,1]
+			{Component: "interest_rate", Indicator: "interest_rate", Transform: TransformLinear, Anchor: AnchorNeutralRate, Scale: 3},                           // 3pp above neutral→1
+			{Component: "real_rate", Indicator: RealRateIndicator, Transform: TransformLinear, Anchor: AnchorRealNeutralRate, Scale: 3},                         // 3pp above real neutral→1
+			// current_account / balance_of_trade are left out: values are in local
+			// currency units and not comparable across countries.
+			{Component: "business_confidence", Indicator: "business_confidence", Transform: TransformLinear, Scale: 100},
+			{Component: "manufacturing_pmi", Indicator: "manufacturing_pmi", Transform: TransformPMI},
+			{Component: "services_pmi", Indicator: "services_pmi", Transform: TransformPMI},
+			{Component: "consumer_confidence", Indicator: "consumer_confidence", Transform: TransformLinear, Scale: 100},
+			{Component: "retail_sales_mom", Indicator: "retail_sales_mom", Transform: TransformLinear, Scale: 2}, // ±2% saturates
+			{Component: "policy_stance", Indicator: PolicyStanceIndicator, Transform: TransformLinear, Scale: 1}, // already in [-1, 1]
+			// momentum: change vs the previous release and 3-/6-release trends, each
+			// at a fifth of a level component's weight
+			{Component: "manufacturing_pmi_change", Indicator: "manufacturing_pmi", Transform: TransformChange, Lookback: 1, Scale: 3, Weight: 0.2},
+			{Component: "manufacturing_pmi_trend3", Indicator: "manufacturing_pmi", Transform: TransformTrend, Lookback: 3, Scale: 1.5, Weight: 0.2},
+			{Component: "manufacturing_pmi_trend6", Indicator: "manufacturing_pmi", Transform: TransformTrend, Lookback: 6, Scale: 1, Weight: 0.2},
+			{Component: "services_pmi_change", Indicator: "services_pmi", Transform: TransformChange, Lookback: 1, Scale: 3, Weight: 0.2},
+			{Component: "services_pmi_trend3", Indicator: "services_pmi", Transform: TransformTrend, Lookback: 3, Scale: 1.5, Weight: 0.2},
+			{Component: "services_pmi_trend6", Indicator: "services_pmi", Transform: TransformTrend, Lookback: 6, Scale: 1, Weight: 0.2},
+			{Component: "unemployment_change", Indicator: "unemployment_rate", Transform: TransformChange, Lookback: 1, Sign: -1, Scale: 0.3, Weight: 0.2}, // rising unemployment is negative
+			{Component: "unemployment_trend3", Indicator: "unemployment_rate", Transform: TransformTrend, Lookback: 3, Sign: -1, Scale: 0.15, Weight: 0.2},
+			{Component: "unemployment_trend6", Indicator: "unemployment_rate", Transform: TransformTrend, Lookback: 6, Sign: -1, Scale: 0.1, Weight: 0.2},
+			{Component: "inflation_change", Indicator: "inflation_rate", Transform: TransformChange, Lookback: 1, Anchor: AnchorInflationTarget, Center: 2, Scale: 0.5, Weight: 0.2}, // moving towards target is positive
+			{Component: "inflation_trend3", Indicator: "inflation_rate", Transform: TransformTrend, Lookback: 3, Anchor: AnchorInflationTarget, Center: 2, Scale: 0.3, Weight: 0.2},
+			{Component: "inflation_trend6", Indicator: "inflation_rate", Transform: TransformTrend, Lookback: 6, Anchor: AnchorInflationTarget, Center: 2, Scale: 0.2, Weight: 0.2},
+			{Component: "interest_rate_change", Indicator: "interest_rate", Transform: TransformChange, Lookback: 1, Scale: 0.5, Weight: 0.2}, // hikes support the currency
+			{Component: "interest_rate_trend3", Indicator: "interest_rate", Transform: TransformTrend, Lookback: 3, Scale: 0.25, Weight: 0.2},
+			{Component: "interest_rate_trend6", Indicator: "interest_rate", Transform: TransformTrend, Lookback: 6, Scale: 0.15, Weight: 0.2},
+		},
+	}
+	if err := m.Validate(); err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Components lists the component keys the model's rules produce, in rule order.
+func (m *ScoringModel) Components() []string {
+	out := make([]string, len(m.Rules))
+	for i, r := range m.Rules {
+		out[i] = r.Component
+	}
+	return out
+}
+
+// Validate checks every rule and fills in defaults (sign, weight, bounds, PMI constants).
+func (m *ScoringModel) Validate() error {
+	if strings.TrimSpace(m.Name) == "" {
+		return fmt.Errorf("scoring model without a name")
+	}
+	if len(m.Rules) == 0 {
+		return fmt.Errorf("model %s: no rules", m.Name)
+	}
+
+	known := make(map[string]bool, len(IndicatorKeys))
+	for _, k := range IndicatorKeys {
+		known[k] = true
+	}
+
+	seen := make(map[string]bool, len(m.Rules))
+	for i := range m.Rules {
+		r := &m.Rules[i]
+		where := fmt.Sprintf("model %s rule %d (%s)", m.Name, i, r.Component)
+
+		if r.Component == "" {
+			return fmt.Errorf("%s: component is required", where)
+		}
+		if seen[r.Component] {
+			return fmt.Errorf("%s: duplicate component", where)
+		}
+		seen[r.Component] = true
+
+		if !known[r.Indicator] {
+			return fmt.Errorf("%s: unknown indicator %q", where, r.Indicator)
+		}
+
+		switch r.Anchor {
+		case "", AnchorInflationTarget, AnchorNeutralRate, AnchorNAIRU, AnchorRealNeutralRate:
+		default:
+			return fmt.Errorf("%s: unknown anchor %q", where, r.Anchor)
+		}
+
+		switch r.Transform {
+		case TransformPMI:
+			if r.Center == 0 {
+				r.Center = 50
+			}
+			if r.Scale == 0 {
+				r.Scale = 10
+			}
+		case TransformLinear, TransformTargetDeviation:
+		case TransformChange:
+			if r.Lookback == 0 {
+				r.Lookback = 1
+			}
+		case TransformTrend:
+			if r.Lookback == 0 {
+				r.Lookback = 3
+			}
+			if r.Lookback < 2 {
+				return fmt.Errorf("%s: trend lookback must be at least 2 releases", where)
+			}
+		case TransformBand:
+			if r.Low > r.High {
+				return fmt.Errorf("%s: band low %.2f above high %.2f", where, r.Low, r.High)
+			}
+		default:
+			return fmt.Errorf("%s: unknown transform %q", where, r.Transform)
+		}
+
+		if r.Scale <= 0 {
+			return fmt.Errorf("%s: scale must be positive", where)
+		}
+
+		if r.Min == 0 && r.Max == 0 {
+			r.Min, r.Max = -1, 1
+		}
+		if r.Min >= r.Max {
+			return fmt.Errorf("%s: min %.2f must be below max %.2f", where, r.Min, r.Max)
+		}
+
+		switch r.Sign {
+		case 0:
+			r.Sign = 1
+		case 1, -1:
+		default:
+			return fmt.Errorf("%s: sign must be 1 or -1", where)
+		}
+
+		if r.Weight == 0 {
+			r.Weight = 1
+		}
+		if r.Weight < 0 {
+			return fmt.Errorf("%s: weight must be positive, use sign to invert", where)
+		}
+	}
+
+	for code, p := range m.CountryParams {
+		if p.InflationLow > p.InflationHigh {
+			return fmt.Errorf("model %s: %s inflation band %.2f-%.2f is inverted", m.Name, code, p.InflationLow, p.InflationHigh)
+		}
+	}
+
+	return nil
+}
+
+// raw is the signed, unclamped transform of v.
+func (r IndicatorRule) raw(v float64) float64 {
+	var x float64
+	switch r.Transform {
+	case TransformLinear, TransformPMI:
+		x = (v - r.Center) / r.Scale
+	case TransformBand:
+		switch {
+		case v > r.High:
+			x = (v - r.High) / r.Scale
+		case v < r.Low:
+			x = (v - r.Low) / r.Scale
+		}
+	case TransformTargetDeviation:
+		x = -math.Abs(v-r.Center) / r.Scale
+	}
+	if x == 0 {
+		return 0 // avoid -0 in JSON when Sign is -1
+	}
+	return r.Sign * x
+}
+
+// IsMomentum reports whether the rule scores release history rather than the latest level.
+func (r IndicatorRule) IsMomentum() bool {
+	return r.Transform == TransformChange || r.Transform == TransformTrend
+}
+
+// momentum is the signed, unclamped momentum transform of a release series
+// (oldest first); ok is false when the series is too short.
+func (r IndicatorRule) momentum(series []float64) (float64, bool) {
+	need := r.Lookback // trend: Lookback releases
+	if r.Transform == TransformChange {
+		need = r.Lookback + 1 // change: latest plus the one Lookback back
+	}
+	n := len(series)
+	if need < 2 || n < need {
+		return 0, false
+	}
+
+	level := func(v float64) float64 {
+		if r.Anchor != "" {
+			return -math.Abs(v - r.Center) // closer to the anchor is higher
+		}
+		return v
+	}
+
+	var delta float64
+	switch r.Transform {
+	case TransformChange:
+		delta = level(series[n-1]) - level(series[n-1-r.Lookback])
+	case TransformTrend:
+		window := series[n-r.Lookback:]
+		ys := make([]float64, len(window))
+		for i, v := range window {
+			ys[i] = level(v)
+		}
+		delta = slope(ys)
+	default:
+		return 0, false
+	}
+
+	x := delta / r.Scale
+	if x == 0 {
+		return 0, true
+	}
+	return r.Sign * x, true
+}
+
+// slope is the least-squares slope of ys against 0, 1, 2, ...
+func slope(ys []float64) float64 {
+	n := float64(len(ys))
+	meanX := (n - 1) / 2
+	var meanY float64
+	for _, y := range ys {
+		meanY += y
+	}
+	meanY /= n
+
+	var num, den float64
+	for i, y := range ys {
+		dx := float64(i) - meanX
+		num += dx * (y - meanY)
+		den += dx * dx
+	}
+	if den == 0 {
+		return 0
+	}
+	return num / den
+}
+
+// Score returns the component value for indicator value v.
+func (r IndicatorRule) Score(v float64) float64 {
+	return clamp(r.raw(v), r.Min, r.Max)
+}
+
+// ModelRegistry holds the named scoring models available to the API and jobs.
+type ModelRegistry struct {
+	models map[string]*ScoringModel
+}
+
+// NewModelRegistry returns a registry containing only the default model.
+func NewModelRegistry() *ModelRegistry {
+	def := DefaultScoringModel()
+	return &ModelRegistry{models: map[string]*ScoringModel{def.Name: def}}
+}
+
+// LoadScoringModels reads a JSON array of models (or a single model object)
+// from path and validates them. A model with extends starts from the named
+// model, the built-in default or one defined earlier in the file. The built-in
+// default cannot be redefined. An empty path yields the built-in default only.
+func LoadScoringModels(path string) (*ModelRegistry, error) {
+	reg := NewModelRegistry()
+	if path == "" {
+		return reg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read scoring models: %w", err)
+	}
+
+	var defs []*ScoringModel
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "{") {
+		var one ScoringModel
+		if err := json.Unmarshal(data, &one); err != nil {
+			return nil, fmt.Errorf("unmarshal scoring model: %w", err)
+		}
+		defs = append(defs, &one)
+	} else if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("unmarshal scoring models: %w", err)
+	}
+
+	loaded := make(map[string]bool, len(defs))
+	for _, m := range defs {
+		if m.Name == DefaultModelName {
+			return nil, fmt.Errorf("scoring model %s is built in and cannot be redefined; add a model that extends it", DefaultModelName)
+		}
+		if loaded[m.Name] {
+			return nil, fmt.Errorf("duplicate scoring model %s", m.Name)
+		}
+		if m.Extends != "" {
+			base, ok := reg.models[m.Extends]
+			if !ok {
+				return nil, fmt.Errorf("scoring model %s extends unknown model %q", m.Name, m.Extends)
+			}
+			if err := m.extend(base); err != nil {
+				return nil, err
+			}
+		}
+		if err := m.Validate(); err != nil {
+			return nil, err
+		}
+		loaded[m.Name] = true
+		reg.models[m.Name] = m
+	}
+
+	return reg, nil
+}
+
+// extend merges base into m: base's rules come first, each replaced by m's rule
+// for the same component, then m's other rules; m's country params override base's.
+func (m *ScoringModel) extend(base *ScoringModel) error {
+	own := make(map[string]IndicatorRule, len(m.Rules))
+	for _, r := range m.Rules {
+		if _, dup := own[r.Component]; dup {
+			return fmt.Errorf("model %s: duplicate component %s", m.Name, r.Component)
+		}
+		own[r.Component] = r
+	}
+
+	rules := make([]IndicatorRule, 0, len(base.Rules)+len(m.Rules))
+	for _, r := range base.Rules {
+		if o, ok := own[r.Component]; ok {
+			r = o
+			delete(own, r.Component)
+		}
+		rules = append(rules, r)
+	}
+	for _, r := range m.Rules {
+		if _, ok := own[r.Component]; ok {
+			rules = append(rules, r)
+		}
+	}
+	m.Rules = rules
+
+	if len(base.CountryParams) > 0 {
+		params := make(map[string]CountryParams, len(base.CountryParams)+len(m.CountryParams))
+		for code, p := range base.CountryParams {
+			params[code] = p
+		}
+		for code, p := range m.CountryParams {
+			params[code] = p
+		}
+		m.CountryParams = params
+	}
+	return nil
+}
+
+// Get returns the named model; an empty name selects the default.
+func (r *ModelRegistry) Get(name string) (*ScoringModel, error) {
+	if name == "" {
+		name = DefaultModelName
+	}
+	m, ok := r.models[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown scoring model %q", name)
+	}
+	return m, nil
+}
+
+// Models returns all registered models sorted by name.
+func (r *ModelRegistry) Models() []*ScoringModel {
+	out := make([]*ScoringModel, 0, len(r.models))
+	for _, m := range r.models {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,271 @@
+package macro
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeModels(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "models.json")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadScoringModelsExtends(t *testing.T) {
+	path := writeModels(t, `[
+		{"name": "tilt", "extends": "default", "rules": [
+			{"component": "interest_rate", "indicator": "interest_rate", "transform": "linear", "scale": 5, "weight": 2},
+			{"component": "surprise", "indicator": "surprise_index", "transform": "linear", "scale": 1.5}
+		], "country_params": {"USD": {"inflation_target": 3, "inflation_low": 3, "inflation_high": 3, "neutral_rate": 4, "nairu": 5}}},
+		{"name": "tilt2", "extends": "tilt", "rules": [
+			{"component": "gdp_growth", "indicator": "gdp_annual_growth_rate", "transform": "linear", "scale": 2}
+		]}
+	]`)
+	reg, err := LoadScoringModels(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	def := DefaultScoringModel()
+	tilt, err := reg.Get("tilt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tilt.Rules) != len(def.Rules)+1 {
+		t.Fatalf("tilt has %d rules, want the %d default rules plus surprise", len(tilt.Rules), len(def.Rules))
+	}
+	for i, r := range def.Rules {
+		if tilt.Rules[i].Component != r.Component {
+			t.Fatalf("rule %d is %s, want %s in base order", i, tilt.Rules[i].Component, r.Component)
+		}
+	}
+	rule, ok := tilt.rule("interest_rate")
+	if !ok || rule.Scale != 5 || rule.Weight != 2 || rule.Anchor != "" {
+		t.Errorf("interest_rate rule = %+v, want the tilt's own rule", rule)
+	}
+	if last := tilt.Rules[len(tilt.Rules)-1]; last.Component != "surprise" || last.Sign != 1 || last.Max != 1 {
+		t.Errorf("last rule = %+v, want the validated surprise rule", last)
+	}
+	if p := tilt.CountryParams["USD"]; p.NeutralRate != 4 {
+		t.Errorf("USD params = %+v, want the tilt's override", p)
+	}
+
+	tilt2, err := reg.Get("tilt2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule, _ := tilt2.rule("interest_rate"); rule.Scale != 5 {
+		t.Errorf("tilt2 interest_rate scale = %v, want 5 inherited from tilt", rule.Scale)
+	}
+	if rule, _ := tilt2.rule("gdp_growth"); rule.Scale != 2 {
+		t.Errorf("tilt2 gdp_growth scale = %v, want 2", rule.Scale)
+	}
+	if p := tilt2.CountryParams["USD"]; p.NeutralRate != 4 {
+		t.Errorf("tilt2 USD params = %+v, want them inherited from tilt", p)
+	}
+
+	if got, _ := reg.Get(""); len(got.Rules) != len(def.Rules) {
+		t.Errorf("default model changed to %d rules", len(got.Rules))
+	}
+}
+
+func TestLoadScoringModelsErrors(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"redefines default", `[{"name": "default", "rules": [{"component": "a", "indicator": "interest_rate", "transform": "linear", "scale": 1}]}]`, "built in"},
+		{"unknown base", `[{"name": "x", "extends": "nope", "rules": []}]`, "unknown model"},
+		{"base defined later", `[{"name": "x", "extends": "y", "rules": []}, {"name": "y", "extends": "default", "rules": []}]`, "unknown model"},
+		{"duplicate own component", `[{"name": "x", "extends": "default", "rules": [
+			{"component": "a", "indicator": "interest_rate", "transform": "linear", "scale": 1},
+			{"component": "a", "indicator": "interest_rate", "transform": "linear", "scale": 2}]}]`, "duplicate component"},
+		{"duplicate model", `[{"name": "x", "extends": "default", "rules": []}, {"name": "x", "extends": "default", "rules": []}]`, "duplicate scoring model"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadScoringModels(writeModels(t, tt.body))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadScoringModelsShippedFile(t *testing.T) {
+	reg, err := LoadScoringModels("../data/scoring_models.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := reg.Get("surprise_tilt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.usesIndicator(SurpriseIndicator) || len(m.Rules) != len(DefaultScoringModel().Rules)+1 {
+		t.Errorf("surprise_tilt should be the default rules plus surprise, got %d rules", len(m.Rules))
+	}
+}
+
+// rule returns the model's rule for a component.
+func (m *ScoringModel) rule(component string) (IndicatorRule, bool) {
+	for _, r := range m.Rules {
+		if r.Component == component {
+			return r, true
+		}
+	}
+	return IndicatorRule{}, false
+}
+
+func TestIndicatorRuleRaw(t *testing.T) {
+	tests := []struct {
+		name string
+		rule IndicatorRule
+		v    float64
+		want float64
+	}{
+		{"linear above center", IndicatorRule{Transform: TransformLinear, Center: 2, Scale: 4, Sign: 1}, 6, 1},
+		{"linear below center", IndicatorRule{Transform: TransformLinear, Center: 2, Scale: 4, Sign: 1}, 0, -0.5},
+		{"linear inverted", IndicatorRule{Transform: TransformLinear, Center: 2, Scale: 1, Sign: -1}, 4, -2},
+		{"pmi", IndicatorRule{Transform: TransformPMI, Center: 50, Scale: 10, Sign: 1}, 55, 0.5},
+		{"inside band", IndicatorRule{Transform: TransformBand, Low: 1, High: 3, Scale: 2, Sign: 1}, 2, 0},
+		{"above band", IndicatorRule{Transform: TransformBand, Low: 1, High: 3, Scale: 2, Sign: 1}, 5, 1},
+		{"below band", IndicatorRule{Transform: TransformBand, Low: 1, High: 3, Scale: 2, Sign: 1}, 0, -0.5},
+		{"over target", IndicatorRule{Transform: TransformTargetDeviation, Center: 2, Scale: 1, Sign: 1}, 3.5, -1.5},
+		{"under target", IndicatorRule{Transform: TransformTargetDeviation, Center: 2, Scale: 1, Sign: 1}, 0.5, -1.5},
+		{"unclamped", IndicatorRule{Transform: TransformLinear, Scale: 1, Sign: 1, Min: -1, Max: 1}, 3, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.raw(tt.v); math.Abs(got-tt.want) > eps {
+				t.Fatalf("raw(%v) = %v, want %v", tt.v, got, tt.want)
+			}
+		})
+	}
+
+	inverted := IndicatorRule{Transform: TransformLinear, Center: 2, Scale: 1, Sign: -1}
+	if got := inverted.raw(2); math.Signbit(got) {
+		t.Errorf("raw at center with sign -1 = %v, want +0", got)
+	}
+}
+
+func TestIndicatorRuleMomentum(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   IndicatorRule
+		series []float64
+		want   float64
+		ok     bool
+	}{
+		{"change vs previous", IndicatorRule{Transform: TransformChange, Lookback: 1, Scale: 1, Sign: 1}, []float64{1, 2, 4}, 2, true},
+		{"change over two releases", IndicatorRule{Transform: TransformChange, Lookback: 2, Scale: 2, Sign: 1}, []float64{1, 2, 4}, 1.5, true},
+		{"change inverted", IndicatorRule{Transform: TransformChange, Lookback: 1, Scale: 1, Sign: -1}, []float64{5, 6}, -1, true},
+		{"change towards the anchor", IndicatorRule{Transform: TransformChange, Lookback: 1, Scale: 1, Sign: 1, Anchor: AnchorInflationTarget, Center: 2}, []float64{4, 3}, 1, true},
+		{"change across the anchor", IndicatorRule{Transform: TransformChange, Lookback: 1, Scale: 1, Sign: 1, Anchor: AnchorInflationTarget, Center: 2}, []float64{1.5, 3}, -0.5, true},
+		{"flat", IndicatorRule{Transform: TransformChange, Lookback: 1, Scale: 1, Sign: -1}, []float64{3, 3}, 0, true},
+		{"change too short", IndicatorRule{Transform: TransformChange, Lookback: 2, Scale: 1, Sign: 1}, []float64{1, 2}, 0, false},
+		{"trend over the last releases", IndicatorRule{Transform: TransformTrend, Lookback: 3, Scale: 1, Sign: 1}, []float64{0, 10, 1, 2, 3}, 1, true},
+		{"trend scaled", IndicatorRule{Transform: TransformTrend, Lookback: 4, Scale: 0.5, Sign: 1}, []float64{1, 2, 1, 2}, 0.4, true},
+		{"trend towards the anchor", IndicatorRule{Transform: TransformTrend, Lookback: 3, Scale: 1, Sign: 1, Anchor: AnchorInflationTarget, Center: 2}, []float64{5, 4, 3}, 1, true},
+		{"trend too short", IndicatorRule{Transform: TransformTrend, Lookback: 3, Scale: 1, Sign: 1}, []float64{1, 2}, 0, false},
+		{"trend of one release", IndicatorRule{Transform: TransformTrend, Lookback: 1, Scale: 1, Sign: 1}, []float64{1, 2}, 0, false},
+		{"level rule", IndicatorRule{Transform: TransformLinear, Lookback: 1, Scale: 1, Sign: 1}, []float64{1, 2, 3}, 0, false},
+		{"no history", IndicatorRule{Transform: TransformChange, Lookback: 1, Scale: 1, Sign: 1}, nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.rule.momentum(tt.series)
+			if ok != tt.ok || math.Abs(got-tt.want) > eps {
+				t.Fatalf("momentum(%v) = %v, %v, want %v, %v", tt.series, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSlope(t *testing.T) {
+	tests := []struct {
+		name string
+		ys   []float64
+		want float64
+	}{
+		{"rising", []float64{1, 3, 5}, 2},
+		{"falling", []float64{3, 2, 1, 0}, -1},
+		{"flat", []float64{2, 2, 2}, 0},
+		{"noisy", []float64{1, 2, 1, 2}, 0.2},
+		{"single point", []float64{5}, 0},
+		{"empty", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slope(tt.ys); math.Abs(got-tt.want) > eps {
+				t.Fatalf("slope(%v) = %v, want %v", tt.ys, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDefaults(t *testing.T) {
+	m := &ScoringModel{Name: "m", Rules: []IndicatorRule{
+		{Component: "pmi", Indicator: "manufacturing_pmi", Transform: TransformPMI},
+		{Component: "change", Indicator: "interest_rate", Transform: TransformChange, Scale: 1},
+		{Component: "trend", Indicator: "interest_rate", Transform: TransformTrend, Scale: 1},
+		{Component: "bounded", Indicator: "interest_rate", Transform: TransformLinear, Scale: 1, Min: 0, Max: 0.5, Sign: -1, Weight: 2},
+	}}
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []IndicatorRule{
+		{Component: "pmi", Indicator: "manufacturing_pmi", Transform: TransformPMI, Center: 50, Scale: 10, Min: -1, Max: 1, Sign: 1, Weight: 1},
+		{Component: "change", Indicator: "interest_rate", Transform: TransformChange, Lookback: 1, Scale: 1, Min: -1, Max: 1, Sign: 1, Weight: 1},
+		{Component: "trend", Indicator: "interest_rate", Transform: TransformTrend, Lookback: 3, Scale: 1, Min: -1, Max: 1, Sign: 1, Weight: 1},
+		{Component: "bounded", Indicator: "interest_rate", Transform: TransformLinear, Scale: 1, Min: 0, Max: 0.5, Sign: -1, Weight: 2},
+	}
+	for i, r := range m.Rules {
+		if r != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, r, want[i])
+		}
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	rule := func(edit func(*IndicatorRule)) []IndicatorRule {
+		r := IndicatorRule{Component: "c", Indicator: "interest_rate", Transform: TransformLinear, Scale: 1}
+		edit(&r)
+		return []IndicatorRule{r}
+	}
+	tests := []struct {
+		name  string
+		model ScoringModel
+		want  string
+	}{
+		{"no name", ScoringModel{Rules: rule(func(*IndicatorRule) {})}, "without a name"},
+		{"no rules", ScoringModel{Name: "m"}, "no rules"},
+		{"no component", ScoringModel{Name: "m", Rules: rule(func(r *IndicatorRule) { r.Component = "" })}, "component is required"},
+		{"duplicate component", ScoringModel{Name: "m", Rules: append(rule(func(*IndicatorRule) {}), rule(func(*IndicatorRule) {})...)}, "duplicate component"},
+		{"unknown indicator", ScoringModel{Name: "m", Rules: rule(func(r *IndicatorRule) { r.Indicator = "gold" })}, "unknown indicator"},
+		{"unknown anchor", ScoringModel{Name: "m", Rules: rule(func(r *IndicatorRule) { r.Anchor = "moon" })}, "unknown anchor"},
+		{"unknown transform", ScoringModel{Name: "m", Rules: rule(func(r *IndicatorRule) { r.Transform = "log" })}, "unknown transform"},
+		{"trend of one release", ScoringModel{Name: "m", Rules: rule(func(r *IndicatorRule) { r.Transform, r.Lookback = TransformTrend, 1 })}, "at least 2"},
+		{"inverted band", ScoringModel{Name: "m", Rules: rule(func(r *IndicatorRule) { r.Transform, r.Low, r.High = TransformBand, 3, 1 })}, "band low"},
+		{"zero scale", ScoringModel{Name: "m", Rules: rule(func(r *IndicatorRule) { r.Scale = 0 })}, "scale must be positive"},
+		{"negative scale", ScoringModel{Name: "m", Rules: rule(func(r *IndicatorRule) { r.Scale = -1 })}, "scale must be positive"},
+		{"min above max", ScoringModel{Name: "m", Rules: rule(func(r *IndicatorRule) { r.Min, r.Max = 1, 0 })}, "must be below max"},
+		{"bad sign", ScoringModel{Name: "m", Rules: rule(func(r *IndicatorRule) { r.Sign = 2 })}, "sign must be"},
+		{"negative weight", ScoringModel{Name: "m", Rules: rule(func(r *IndicatorRule) { r.Weight = -1 })}, "use sign to invert"},
+		{"inverted inflation band", ScoringModel{Name: "m", Rules: rule(func(*IndicatorRule) {}),
+			CountryParams: map[string]CountryParams{"USD": {InflationLow: 3, InflationHigh: 1}}}, "inflation band"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package macro
+
+// usesMomentum reports whether any of the model's rules reads release history.
+func (m *ScoringModel) usesMomentum() bool {
+	for _, rule := range m.Rules {
+		if rule.IsMomentum() {
+			return true
+		}
+	}
+	return false
+}
+
+// historyReleases is how many releases per indicator history normalisation
+// z-scores against, about two years of monthly data.
+const historyReleases = 24
+
+// historyDepth is the number of most recent releases per indicator the model
+// reads: enough for its longest momentum lookback and, with history
+// normalisation, for the z-score sample.
+func (m *ScoringModel) historyDepth(normalization string) int {
+	depth := 0
+	if normalization == NormalizationHistory {
+		depth = historyReleases
+	}
+	for _, rule := range m.Rules {
+		if rule.IsMomentum() {
+			depth = max(depth, rule.Lookback+1)
+		}
+	}
+	return depth
+}
+
+// splitMomentum separates level components from momentum components.
+func splitMomentum(model *ScoringModel, components map[string]float64) (map[string]float64, map[string]float64) {
+	momentumRules := make(map[string]bool)
+	for _, rule := range model.Rules {
+		if rule.IsMomentum() {
+			momentumRules[rule.Component] = true
+		}
+	}
+
+	levels := make(map[string]float64, len(components))
+	momentum := make(map[string]float64)
+	for k, v := range components {
+		if momentumRules[k] {
+			momentum[k] = v
+		} else {
+			levels[k] = v
+		}
+	}
+	return levels, momentum
+}
+
+// explainMomentum averages the momentum components of each indicator and names
+// the indicators whose recent direction helps or hurts the currency.
+func explainMomentum(model *ScoringModel, momentum map[string]float64) string {
+	if len(momentum) == 0 {
+		return ""
+	}
+
+	var order []IndicatorRule // first momentum rule per indicator
+	sums := make(map[string]float64)
+	counts := make(map[string]float64)
+	for _, rule := range model.Rules {
+		v, ok := momentum[rule.Component]
+		if !ok {
+			continue
+		}
+		if counts[rule.Indicator] == 0 {
+			order = append(order, rule)
+		}
+		sums[rule.Indicator] += v
+		counts[rule.Indicator]++
+	}
+
+	var supports, drags []string
+	for _, rule := range order {
+		switch avg := sums[rule.Indicator] / counts[rule.Indicator]; {
+		case avg > 0.15:
+			supports = append(supports, momentumLabel(rule, avg))
+		case avg < -0.15:
+			drags = append(drags, momentumLabel(rule, avg))
+		}
+	}
+
+	text := ""
+	if len(supports) > 0 {
+		text += " Recent momentum supports it: " + joinWithAnd(supports) + "."
+	}
+	if len(drags) > 0 {
+		text += " Recent momentum weighs on it: " + joinWithAnd(drags) + "."
+	}
+	return text
+}
+
+// momentumLabel describes the move behind a momentum reading, e.g. "falling Unemployment Rate".
+func momentumLabel(rule IndicatorRule, avg float64) string {
+	label := rule.Indicator
+	if def, ok := LookupIndicator(rule.Indicator); ok {
+		label = def.Label
+	}
+
+	if rule.Anchor != "" {
+		if avg > 0 {
+			return label + " moving towards target"
+		}
+		return label + " moving away from target"
+	}
+	if avg*rule.Sign > 0 {
+		return "rising " + label
+	}
+	return "falling " + label
+}
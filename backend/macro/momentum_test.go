@@ -0,0 +1,28 @@
+package macro
+
+import "testing"
+
+func TestHistoryDepth(t *testing.T) {
+	levels := &ScoringModel{Rules: []IndicatorRule{{Component: "rate", Indicator: "interest_rate", Transform: TransformLinear}}}
+	tests := []struct {
+		name          string
+		model         *ScoringModel
+		normalization string
+		want          int
+	}{
+		{"levels only", levels, NormalizationAbsolute, 0},
+		{"levels against history", levels, NormalizationHistory, historyReleases},
+		{"default momentum", DefaultScoringModel(), NormalizationAbsolute, 7},
+		{"momentum against history", DefaultScoringModel(), NormalizationHistory, historyReleases},
+		{"lookback beyond the history window", &ScoringModel{Rules: []IndicatorRule{
+			{Component: "c", Indicator: "interest_rate", Transform: TransformChange, Lookback: 30},
+		}}, NormalizationHistory, 31},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.model.historyDepth(tt.normalization); got != tt.want {
+				t.Fatalf("historyDepth = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package macro
+
+import (
+	"fmt"
+	"math"
+)
+
+// Normalisation modes for ScoreOptions.Normalization.
+const (
+	NormalizationAbsolute     = "absolute"      // fixed scales from the model rules
+	NormalizationCrossSection = "cross_section" // z-score against the other currencies in the set
+	NormalizationHistory      = "history"       // z-score against the currency's own release history
+)
+
+// zSaturation is the z-score that maps to a ±1 component.
+const zSaturation = 2.0
+
+// minHistory is the fewest releases needed to z-score against own history.
+const minHistory = 3
+
+// IndicatorHistory holds past indicator values per currency and indicator key.
+type IndicatorHistory map[string]map[string][]float64
+
+// ParseNormalization validates a normalisation mode; empty selects absolute.
+func ParseNormalization(v string) (string, error) {
+	switch v {
+	case "":
+		return NormalizationAbsolute, nil
+	case NormalizationAbsolute, NormalizationCrossSection, NormalizationHistory:
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown normalization %q (want absolute, cross_section or history)", v)
+}
+
+// scoreCrossSection z-scores each component's raw transform across all snapshots,
+// so a currency scores by how it ranks against its peers.
+func scoreCrossSection(snapshots []MacroSnapshot, model *ScoringModel, opts ScoreOptions) map[string]ScoreBreakdown {
+	raws := make([]map[string]float64, len(snapshots))
+	for i, s := range snapshots {
+		raws[i] = rawComponents(s, model, opts.History[s.Country])
+	}
+
+	out := make(map[string]ScoreBreakdown, len(snapshots))
+	for i, s := range snapshots {
+		components := make(map[string]float64, len(raws[i]))
+		for _, rule := range model.Rules {
+			v, ok := raws[i][rule.Component]
+			if !ok {
+				continue
+			}
+			var peers []float64
+			for _, r := range raws {
+				if pv, ok := r[rule.Component]; ok {
+					peers = append(peers, pv)
+				}
+			}
+			components[rule.Component] = clamp(zScore(v, peers)/zSaturation, rule.Min, rule.Max)
+		}
+		out[s.Country] = newBreakdown(s, model, NormalizationCrossSection, components, opts)
+	}
+	return out
+}
+
+// scoreAgainstHistory z-scores each component against the same transform applied
+// to the currency's past releases. Components without enough history are omitted.
+func scoreAgainstHistory(snapshots []MacroSnapshot, model *ScoringModel, opts ScoreOptions) map[string]ScoreBreakdown {
+	out := make(map[string]ScoreBreakdown, len(snapshots))
+	for _, s := range snapshots {
+		params, hasParams := model.paramsFor(s.Country)
+		past := opts.History[s.Country]
+
+		components := make(map[string]float64, len(model.Rules))
+		for _, rule := range model.Rules {
+			r := rule.anchored(params, hasParams)
+			values := past[rule.Indicator]
+			if rule.IsMomentum() {
+				// momentum is already a change over history; score it as is
+				if v, ok := r.momentum(values); ok {
+					components[rule.Component] = clamp(v, rule.Min, rule.Max)
+				}
+				continue
+			}
+
+			v, ok := s.Indicator(rule.Indicator)
+			if !ok || len(values) < minHistory {
+				continue
+			}
+
+			series := make([]float64, len(values))
+			for i, hv := range values {
+				series[i] = r.raw(hv)
+			}
+			components[rule.Component] = clamp(zScore(r.raw(v), series)/zSaturation, rule.Min, rule.Max)
+		}
+		out[s.Country] = newBreakdown(s, model, NormalizationHistory, components, opts)
+	}
+	return out
+}
+
+// zScore standardises v against sample; 0 when the sample has no spread.
+func zScore(v float64, sample []float64) float64 {
+	if len(sample) < 2 {
+		return 0
+	}
+
+	mean, std := meanStd(sample)
+	if std == 0 {
+		return 0
+	}
+	return (v - mean) / std
+}
+
+// meanStd returns the mean and sample standard deviation; sample needs at least two values.
+func meanStd(sample []float64) (float64, float64) {
+	var mean float64
+	for _, x := range sample {
+		mean += x
+	}
+	mean /= float64(len(sample))
+
+	var variance float64
+	for _, x := range sample {
+		variance += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(sample)-1))
+}
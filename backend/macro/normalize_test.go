@@ -0,0 +1,105 @@
+package macro
+
+import (
+	"math"
+	"testing"
+)
+
+const eps = 1e-9
+
+func TestMeanStd(t *testing.T) {
+	tests := []struct {
+		name            string
+		sample          []float64
+		wantMean, wantS float64
+	}{
+		{"two values", []float64{-1, 1}, 0, math.Sqrt(2)},
+		{"unit spread", []float64{1, 2, 3}, 2, 1},
+		{"sample, not population", []float64{2, 4, 4, 4, 5, 5, 7, 9}, 5, math.Sqrt(32.0 / 7)},
+		{"constant", []float64{3, 3, 3}, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mean, std := meanStd(tt.sample)
+			if math.Abs(mean-tt.wantMean) > eps || math.Abs(std-tt.wantS) > eps {
+				t.Fatalf("meanStd(%v) = %v, %v, want %v, %v", tt.sample, mean, std, tt.wantMean, tt.wantS)
+			}
+		})
+	}
+}
+
+func TestZScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		v      float64
+		sample []float64
+		want   float64
+	}{
+		{"above the mean", 4, []float64{1, 2, 3}, 2},
+		{"below the mean", 0, []float64{1, 2, 3}, -2},
+		{"at the mean", 5, []float64{2, 4, 4, 4, 5, 5, 7, 9}, 0},
+		{"sample std", 9, []float64{2, 4, 4, 4, 5, 5, 7, 9}, 4 / math.Sqrt(32.0/7)},
+		{"no spread", 5, []float64{2, 2, 2}, 0},
+		{"single value", 5, []float64{1}, 0},
+		{"empty", 5, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := zScore(tt.v, tt.sample); math.Abs(got-tt.want) > eps {
+				t.Fatalf("zScore(%v, %v) = %v, want %v", tt.v, tt.sample, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreCrossSection(t *testing.T) {
+	model := &ScoringModel{Name: "peers", Rules: []IndicatorRule{
+		{Component: "rates", Indicator: "interest_rate", Transform: TransformLinear, Scale: 1},
+		{Component: "jobs", Indicator: "unemployment_rate", Transform: TransformLinear, Scale: 1, Sign: -1},
+	}}
+	if err := model.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string][2]float64{ // interest rate, unemployment rate
+		"USD": {3, 5},
+		"EUR": {2, 4},
+		"JPY": {1, 3},
+	}
+	var snapshots []MacroSnapshot
+	for code, v := range values {
+		s := MacroSnapshot{Country: code}
+		s.setIndicator("interest_rate", v[0])
+		s.setIndicator("unemployment_rate", v[1])
+		snapshots = append(snapshots, s)
+	}
+	gbp := MacroSnapshot{Country: "GBP"} // no unemployment rate: only ranked on rates
+	gbp.setIndicator("interest_rate", 2)
+	snapshots = append(snapshots, gbp)
+
+	scores := scoreCrossSection(snapshots, model, ScoreOptions{})
+
+	// rates 3, 2, 1, 2: mean 2, sample std sqrt(2/3); jobs -5, -4, -3: z -1, 0, 1
+	rateZ := 1 / math.Sqrt(2.0/3) / zSaturation
+	want := map[string]map[string]float64{
+		"USD": {"rates": rateZ, "jobs": -0.5},
+		"EUR": {"rates": 0, "jobs": 0},
+		"JPY": {"rates": -rateZ, "jobs": 0.5},
+		"GBP": {"rates": 0},
+	}
+	for code, comps := range want {
+		got := scores[code].Components
+		if len(got) != len(comps) {
+			t.Errorf("%s components = %v, want %v", code, got, comps)
+			continue
+		}
+		for k, v := range comps {
+			if math.Abs(got[k]-v) > 1e-3 {
+				t.Errorf("%s %s = %v, want %v", code, k, got[k], v)
+			}
+		}
+	}
+	if scores["USD"].Normalization != NormalizationCrossSection {
+		t.Errorf("normalization = %q", scores["USD"].Normalization)
+	}
+}
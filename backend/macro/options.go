@@ -0,0 +1,48 @@
+package macro
+
+import "time"
+
+// ScoreOptions controls how snapshots are assembled and scored.
+type ScoreOptions struct {
+	// AsOf restricts snapshots to releases dated and ingested at or before
+	// this moment. Zero means the latest data.
+	AsOf time.Time
+	// ReleaseTimeOnly drops the ingested_at condition for AsOf, for
+	// backfills over history that was ingested after the fact.
+	ReleaseTimeOnly bool
+	// Model scores the snapshots; nil selects the default model.
+	Model *ScoringModel
+	// Normalization is one of the Normalization* modes; empty means absolute.
+	Normalization string
+	// History feeds NormalizationHistory; SnapshotRepository.Scores fills it in.
+	History IndicatorHistory
+	// MinCoverage is the data coverage below which a score is flagged as
+	// low-confidence; zero selects DefaultMinCoverage.
+	MinCoverage float64
+	// DecayHalfLife, when positive, halves a component's weight for every
+	// half-life of age of its indicator release.
+	DecayHalfLife time.Duration
+}
+
+// DefaultMinCoverage is the coverage threshold used when ScoreOptions.MinCoverage is zero.
+const DefaultMinCoverage = 0.6
+
+// PointInTime reports whether the options ask for a historical view.
+func (o ScoreOptions) PointInTime() bool {
+	return !o.AsOf.IsZero()
+}
+
+func (o ScoreOptions) minCoverage() float64 {
+	if o.MinCoverage > 0 {
+		return o.MinCoverage
+	}
+	return DefaultMinCoverage
+}
+
+// referenceTime is the moment indicator ages are measured from.
+func (o ScoreOptions) referenceTime() time.Time {
+	if o.PointInTime() {
+		return o.AsOf.UTC()
+	}
+	return time.Now().UTC()
+}
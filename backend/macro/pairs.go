@@ -1,6 +1,9 @@
 package macro
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // PairSentiment PairSentiment
 type PairSentiment struct {
@@ -12,15 +15,37 @@ type PairSentiment struct {
 	BaseDetails  ScoreBreakdown `json:"base_details"`
 	QuoteDetails ScoreBreakdown `json:"quote_details"`
 	Explanation  string         `json:"explanation"`
+
+	// rate differentials, base minus quote in pp; nil when either side lacks data.
+	// Components holds them scaled to [-1, 1]; they explain the pair score but
+	// are not added to it, since both rates already feed the currency totals.
+	Carry                *float64           `json:"carry,omitempty"`
+	RealRateDifferential *float64           `json:"real_rate_differential,omitempty"`
+	Components           map[string]float64 `json:"components"`
 }
 
 // BuildScoresByCountry BuildScoresByCountry
-func BuildScoresByCountry(snapshots []MacroSnapshot) map[string]ScoreBreakdown {
-	out := make(map[string]ScoreBreakdown, len(snapshots))
-	for _, s := range snapshots {
-		score := ScoreSnapshot(s)
-		out[s.Country] = score
+func BuildScoresByCountry(snapshots []MacroSnapshot, opts ScoreOptions) map[string]ScoreBreakdown {
+	model := opts.Model
+	if model == nil {
+		model = DefaultScoringModel()
+	}
+
+	var out map[string]ScoreBreakdown
+	switch opts.Normalization {
+	case NormalizationCrossSection:
+		out = scoreCrossSection(snapshots, model, opts)
+	case NormalizationHistory:
+		out = scoreAgainstHistory(snapshots, model, opts)
+	default:
+		out = make(map[string]ScoreBreakdown, len(snapshots))
+		for _, s := range snapshots {
+			score := scoreAbsolute(s, model, opts)
+			out[s.Country] = score
+		}
 	}
+
+	flagLowConfidence(out, opts.minCoverage())
 	return out
 }
 
@@ -29,9 +54,13 @@ func PairSentimentFromSnapshots(
 	snapshots []MacroSnapshot,
 	base string,
 	quote string,
+	opts ScoreOptions,
 ) (PairSentiment, error) {
-	scores := BuildScoresByCountry(snapshots)
+	return PairSentimentFromScores(BuildScoresByCountry(snapshots, opts), base, quote)
+}
 
+// PairSentimentFromScores compares two already scored currencies.
+func PairSentimentFromScores(scores map[string]ScoreBreakdown, base, quote string) (PairSentiment, error) {
 	baseScore, okB := scores[base]
 	quoteScore, okQ := scores[quote]
 	if !okB || !okQ {
@@ -39,17 +68,22 @@ func PairSentimentFromSnapshots(
 	}
 
 	pairScore := round(baseScore.TotalScore-quoteScore.TotalScore, 3)
+	carry, realDiff, components := rateDifferentials(baseScore, quoteScore)
 	explanation := explainPair(baseScore, quoteScore, pairScore)
+	explanation += explainCarry(base, quote, carry, realDiff)
 
 	return PairSentiment{
-		Base:         base,
-		Quote:        quote,
-		BaseScore:    baseScore.TotalScore,
-		QuoteScore:   quoteScore.TotalScore,
-		PairScore:    pairScore,
-		BaseDetails:  baseScore,
-		QuoteDetails: quoteScore,
-		Explanation:  explanation,
+		Base:                 base,
+		Quote:                quote,
+		BaseScore:            baseScore.TotalScore,
+		QuoteScore:           quoteScore.TotalScore,
+		PairScore:            pairScore,
+		BaseDetails:          baseScore,
+		QuoteDetails:         quoteScore,
+		Explanation:          explanation,
+		Carry:                carry,
+		RealRateDifferential: realDiff,
+		Components:           components,
 	}, nil
 }
 
@@ -116,6 +150,8 @@ func kToLabel(key string) string {
 		return "inflation stability"
 	case "interest_rate":
 		return "interest rate advantage"
+	case "real_rate":
+		return "real rate advantage"
 	case "current_account":
 		return "current account balance"
 	case "balance_of_trade":
@@ -130,9 +166,21 @@ func kToLabel(key string) string {
 		return "consumer confidence"
 	case "retail_sales_mom":
 		return "retail sales momentum"
-	default:
-		return key
+	case "policy_stance":
+		return "central bank stance"
+	}
+
+	// momentum components: <level component>_change / _trend3 / _trend6
+	for _, m := range []struct{ suffix, label string }{
+		{"_change", "latest change in "},
+		{"_trend3", "3-release trend in "},
+		{"_trend6", "6-release trend in "},
+	} {
+		if base, ok := strings.CutSuffix(key, m.suffix); ok {
+			return m.label + kToLabel(base)
+		}
 	}
+	return key
 }
 
 func explainEdge(label string, favoursBase bool) string {
@@ -147,6 +195,10 @@ func explainEdge(label string, favoursBase bool) string {
 			return "more stable inflation"
 		case "interest rate advantage":
 			return "higher interest rates"
+		case "real rate advantage":
+			return "higher real rates"
+		case "central bank stance":
+			return "a more hawkish central bank"
 		default:
 			return label
 		}
@@ -0,0 +1,64 @@
+package macro
+
+// Anchors a rule can take its center from, see IndicatorRule.Anchor.
+const (
+	AnchorInflationTarget = "inflation_target"
+	AnchorNeutralRate     = "neutral_rate"
+	AnchorNAIRU           = "nairu"
+	AnchorRealNeutralRate = "real_neutral_rate" // neutral rate minus inflation target
+)
+
+// CountryParams are the structural levels a currency's indicators are scored against.
+type CountryParams struct {
+	InflationTarget float64 `json:"inflation_target"` // central-bank point target (midpoint for band targets)
+	InflationLow    float64 `json:"inflation_low"`    // lower edge of the target band
+	InflationHigh   float64 `json:"inflation_high"`   // upper edge of the target band
+	NeutralRate     float64 `json:"neutral_rate"`     // estimated nominal neutral policy rate
+	NAIRU           float64 `json:"nairu"`            // structural unemployment rate
+}
+
+// DefaultCountryParams holds central-bank targets and rough consensus estimates
+// of neutral rates and NAIRU. Models can override them per currency.
+var DefaultCountryParams = map[string]CountryParams{
+	"USD": {InflationTarget: 2, InflationLow: 2, InflationHigh: 2, NeutralRate: 3.0, NAIRU: 4.2},
+	"EUR": {InflationTarget: 2, InflationLow: 2, InflationHigh: 2, NeutralRate: 2.0, NAIRU: 6.5},
+	"GBP": {InflationTarget: 2, InflationLow: 2, InflationHigh: 2, NeutralRate: 3.25, NAIRU: 4.5},
+	"JPY": {InflationTarget: 2, InflationLow: 2, InflationHigh: 2, NeutralRate: 1.0, NAIRU: 2.5},
+	"AUD": {InflationTarget: 2.5, InflationLow: 2, InflationHigh: 3, NeutralRate: 3.5, NAIRU: 4.5},
+	"NZD": {InflationTarget: 2, InflationLow: 1, InflationHigh: 3, NeutralRate: 3.0, NAIRU: 4.5},
+	"CHF": {InflationTarget: 1, InflationLow: 0, InflationHigh: 2, NeutralRate: 0.5, NAIRU: 2.8},
+	"CAD": {InflationTarget: 2, InflationLow: 1, InflationHigh: 3, NeutralRate: 2.75, NAIRU: 6.0},
+}
+
+// paramsFor returns the model override for a currency, else the default table entry.
+func (m *ScoringModel) paramsFor(country string) (CountryParams, bool) {
+	if p, ok := m.CountryParams[country]; ok {
+		return p, true
+	}
+	p, ok := DefaultCountryParams[country]
+	return p, ok
+}
+
+// anchored returns a copy of the rule centred on the country's parameter.
+// Rules without an anchor, or currencies without parameters, keep their own center/band.
+func (r IndicatorRule) anchored(p CountryParams, ok bool) IndicatorRule {
+	if r.Anchor == "" || !ok {
+		return r
+	}
+
+	switch r.Anchor {
+	case AnchorInflationTarget:
+		r.Center = p.InflationTarget
+		r.Low, r.High = p.InflationLow, p.InflationHigh
+	case AnchorNeutralRate:
+		r.Center = p.NeutralRate
+		r.Low, r.High = p.NeutralRate, p.NeutralRate
+	case AnchorNAIRU:
+		r.Center = p.NAIRU
+		r.Low, r.High = p.NAIRU, p.NAIRU
+	case AnchorRealNeutralRate:
+		r.Center = p.NeutralRate - p.InflationTarget
+		r.Low, r.High = r.Center, r.Center
+	}
+	return r
+}
@@ -0,0 +1,91 @@
+package macro
+
+import (
+	"context"
+	"economic_indicator/models"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// SaveScores writes one currency_scores row per known currency and one
+// instrument_scores row per known instrument, all stamped with ts and model.
+// Scores for codes/symbols missing from the reference tables are skipped.
+func SaveScores(
+	ctx context.Context,
+	db *bun.DB,
+	ts time.Time,
+	model string,
+	currencyScores map[string]ScoreBreakdown,
+	instrumentScores map[string]InstrumentScore,
+) error {
+	var currencies []models.Currency
+	if err := db.NewSelect().Model(&currencies).Scan(ctx); err != nil {
+		return fmt.Errorf("select currencies: %w", err)
+	}
+	currencyIDs := make(map[string]int64, len(currencies))
+	for _, c := range currencies {
+		currencyIDs[c.Code] = c.ID
+	}
+
+	var instruments []models.Instrument
+	if err := db.NewSelect().Model(&instruments).Scan(ctx); err != nil {
+		return fmt.Errorf("select instruments: %w", err)
+	}
+	instrumentIDs := make(map[string]int64, len(instruments))
+	for _, inst := range instruments {
+		instrumentIDs[inst.Symbol] = inst.ID
+	}
+
+	ts = ts.UTC()
+
+	var curRows []models.CurrencyScore
+	for code, score := range currencyScores {
+		id, ok := currencyIDs[code]
+		if !ok {
+			log.Printf("no currency row for %s, skipping score", code)
+			continue
+		}
+		curRows = append(curRows, models.CurrencyScore{
+			CurrencyID:  id,
+			TS:          ts,
+			EconScore:   score.TotalScore,
+			Model:       model,
+			Components:  score.Components,
+			Explanation: score.Explanation,
+		})
+	}
+
+	var instRows []models.InstrumentScore
+	for symbol, score := range instrumentScores {
+		id, ok := instrumentIDs[symbol]
+		if !ok {
+			log.Printf("no instrument row for %s, skipping score", symbol)
+			continue
+		}
+		instRows = append(instRows, models.InstrumentScore{
+			InstrumentID: id,
+			TS:           ts,
+			FinalScore:   score.TotalScore,
+			Model:        model,
+			Components:   score.Components,
+			Explanation:  score.Explanation,
+		})
+	}
+
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if len(curRows) > 0 {
+			if _, err := tx.NewInsert().Model(&curRows).Exec(ctx); err != nil {
+				return fmt.Errorf("insert currency scores: %w", err)
+			}
+		}
+		if len(instRows) > 0 {
+			if _, err := tx.NewInsert().Model(&instRows).Exec(ctx); err != nil {
+				return fmt.Errorf("insert instrument scores: %w", err)
+			}
+		}
+		return nil
+	})
+}
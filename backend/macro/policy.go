@@ -0,0 +1,284 @@
+package macro
+
+import (
+	"context"
+	"economic_indicator/models"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// PolicyStanceIndicator is the derived indicator key carrying a currency's
+// central-bank policy stance; scoring models can use it like any ingested indicator.
+const PolicyStanceIndicator = "policy_stance"
+
+// Guidance tags a decision can carry.
+const (
+	GuidanceHawkish = "hawkish"
+	GuidanceDovish  = "dovish"
+	GuidanceNeutral = "neutral"
+)
+
+// Policy stance defaults: rate moves count for a year, halving in weight every
+// six months, and three 25bp moves in the same direction saturate the move term.
+const (
+	DefaultPolicyWindow   = 365 * day
+	policyMoveHalfLife    = 180 * day
+	policyMoveScale       = 0.75
+	policyMeetingHorizon  = 45 * day // guidance for a meeting further out counts half
+	policyMoveWeight      = 0.6
+	policyGuidanceWeight  = 0.4
+	policyGuidanceTagPart = 0.6 // rest of the forward-looking term comes from the vote split
+)
+
+// CentralBanks names the central bank setting each currency's policy rate.
+var CentralBanks = map[string]string{
+	"USD": "Federal Reserve",
+	"EUR": "European Central Bank",
+	"GBP": "Bank of England",
+	"JPY": "Bank of Japan",
+	"AUD": "Reserve Bank of Australia",
+	"NZD": "Reserve Bank of New Zealand",
+	"CHF": "Swiss National Bank",
+	"CAD": "Bank of Canada",
+}
+
+// ParseGuidance normalises a guidance tag; empty input is neutral.
+func ParseGuidance(v string) (string, error) {
+	switch g := strings.ToLower(strings.TrimSpace(v)); g {
+	case GuidanceHawkish, GuidanceDovish, GuidanceNeutral:
+		return g, nil
+	case "":
+		return GuidanceNeutral, nil
+	}
+	return "", fmt.Errorf("unknown guidance %q (want hawkish, dovish or neutral)", v)
+}
+
+// PolicyOptions controls the policy stance calculation.
+type PolicyOptions struct {
+	AsOf            time.Time     // zero means now
+	ReleaseTimeOnly bool          // see ScoreOptions.ReleaseTimeOnly
+	Window          time.Duration // zero selects DefaultPolicyWindow
+}
+
+// PolicyDecision is one central-bank rate decision.
+type PolicyDecision struct {
+	DateTime    time.Time  `json:"datetime"`
+	Rate        float64    `json:"rate"`
+	Change      float64    `json:"change"` // pp versus the previous stored decision
+	VotesHike   int        `json:"votes_hike"`
+	VotesHold   int        `json:"votes_hold"`
+	VotesCut    int        `json:"votes_cut"`
+	Guidance    string     `json:"guidance"`
+	NextMeeting *time.Time `json:"next_meeting,omitempty"`
+}
+
+// voteTilt is (hike votes - cut votes) / all votes, 0 when no split was published.
+func (d PolicyDecision) voteTilt() float64 {
+	total := d.VotesHike + d.VotesHold + d.VotesCut
+	if total == 0 {
+		return 0
+	}
+	return float64(d.VotesHike-d.VotesCut) / float64(total)
+}
+
+// PolicyStance summarises a currency's recent central-bank decisions. Stance is
+// in [-1, 1]; positive means policy is tightening, which supports the currency.
+type PolicyStance struct {
+	Currency          string           `json:"currency"`
+	Bank              string           `json:"bank,omitempty"`
+	Rate              float64          `json:"rate"`
+	Direction         string           `json:"direction"`  // hiking, cutting or on hold over the window
+	NetChange         float64          `json:"net_change"` // pp moved inside the window
+	Guidance          string           `json:"guidance"`   // latest decision's guidance
+	VoteTilt          float64          `json:"vote_tilt"`  // latest decision's (hike - cut) / votes
+	MoveScore         float64          `json:"move_score"` // decay-weighted recent moves, [-1, 1]
+	GuidanceScore     float64          `json:"guidance_score"`
+	NextMeeting       *time.Time       `json:"next_meeting,omitempty"`
+	DaysToNextMeeting *float64         `json:"days_to_next_meeting,omitempty"`
+	Stance            float64          `json:"stance"`
+	AsOf              time.Time        `json:"as_of"`
+	Explanation       string           `json:"explanation"`
+	Decisions         []PolicyDecision `json:"decisions"` // newest first
+}
+
+// PolicyStances computes the policy stance of every currency with stored
+// decisions, or only of the given currency codes.
+func (r *SnapshotRepository) PolicyStances(ctx context.Context, opts PolicyOptions, codes ...string) (map[string]PolicyStance, error) {
+	if r.DB == nil {
+		return nil, fmt.Errorf("no database configured")
+	}
+
+	var rows []models.PolicyDecision
+	q := r.DB.NewSelect().
+		Model(&rows).
+		Apply(asOfFilter(ScoreOptions{AsOf: opts.AsOf, ReleaseTimeOnly: opts.ReleaseTimeOnly})).
+		Order("datetime ASC", "revision DESC")
+	if len(codes) > 0 {
+		q = q.Where("currency IN (?)", bun.In(codes))
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("select policy decisions: %w", err)
+	}
+
+	// a revised decision counts with its latest revision known at opts.AsOf
+	byCurrency := make(map[string][]PolicyDecision)
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		code := strings.ToUpper(row.Currency)
+		key := code + "|" + row.DateTime.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		byCurrency[code] = append(byCurrency[code], PolicyDecision{
+			DateTime:    row.DateTime.UTC(),
+			Rate:        row.Rate,
+			VotesHike:   row.VotesHike,
+			VotesHold:   row.VotesHold,
+			VotesCut:    row.VotesCut,
+			Guidance:    row.Guidance,
+			NextMeeting: row.NextMeeting,
+		})
+	}
+
+	out := make(map[string]PolicyStance, len(byCurrency))
+	for code, decisions := range byCurrency {
+		out[code] = buildPolicyStance(code, decisions, opts)
+	}
+	return out, nil
+}
+
+// buildPolicyStance scores decisions (oldest first). The stance blends the
+// decay-weighted rate moves inside the window with the latest decision's
+// guidance and vote split; the forward-looking part counts in full only when
+// the next meeting is known and close.
+func buildPolicyStance(code string, decisions []PolicyDecision, opts PolicyOptions) PolicyStance {
+	now := opts.AsOf
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	window := opts.Window
+	if window <= 0 {
+		window = DefaultPolicyWindow
+	}
+
+	for i := 1; i < len(decisions); i++ {
+		decisions[i].Change = round(decisions[i].Rate-decisions[i-1].Rate, 3)
+	}
+
+	var moves, net float64
+	for _, d := range decisions {
+		age := now.Sub(d.DateTime)
+		if age < 0 || age > window {
+			continue
+		}
+		moves += d.Change * math.Pow(0.5, float64(age)/float64(policyMoveHalfLife))
+		net += d.Change
+	}
+
+	latest := decisions[len(decisions)-1]
+	guidance, err := ParseGuidance(latest.Guidance)
+	if err != nil {
+		guidance = GuidanceNeutral
+	}
+	tag := 0.0
+	switch guidance {
+	case GuidanceHawkish:
+		tag = 1
+	case GuidanceDovish:
+		tag = -1
+	}
+	tilt := latest.voteTilt()
+
+	s := PolicyStance{
+		Currency:      code,
+		Bank:          CentralBanks[code],
+		Rate:          latest.Rate,
+		Direction:     "on hold",
+		NetChange:     round(net, 3),
+		Guidance:      guidance,
+		VoteTilt:      round(tilt, 3),
+		MoveScore:     round(clamp(moves/policyMoveScale, -1, 1), 3),
+		GuidanceScore: round(policyGuidanceTagPart*tag+(1-policyGuidanceTagPart)*tilt, 3),
+		AsOf:          now,
+	}
+	switch {
+	case s.NetChange > 0:
+		s.Direction = "hiking"
+	case s.NetChange < 0:
+		s.Direction = "cutting"
+	}
+
+	// a next-meeting date that has already passed without a stored decision is stale
+	proximity := 0.5
+	if next := latest.NextMeeting; next != nil && next.After(now) {
+		t := next.UTC()
+		days := round(t.Sub(now).Hours()/24, 1)
+		s.NextMeeting, s.DaysToNextMeeting = &t, &days
+		if t.Sub(now) <= policyMeetingHorizon {
+			proximity = 1
+		}
+	}
+
+	stance := policyMoveWeight*s.MoveScore + policyGuidanceWeight*proximity*s.GuidanceScore
+	if stance = round(clamp(stance, -1, 1), 3); stance != 0 {
+		s.Stance = stance // keep -0 out of the JSON
+	}
+
+	s.Decisions = make([]PolicyDecision, len(decisions))
+	for i, d := range decisions {
+		s.Decisions[len(decisions)-1-i] = d
+	}
+	s.Explanation = explainPolicy(s, latest, window)
+	return s
+}
+
+func explainPolicy(s PolicyStance, latest PolicyDecision, window time.Duration) string {
+	bank := s.Bank
+	if bank == "" {
+		bank = s.Currency + " central bank"
+	}
+
+	months := int(window / (30 * day))
+	var text string
+	switch s.Direction {
+	case "hiking":
+		text = fmt.Sprintf("The %s has raised rates by %.2fpp over the last %d months to %.2f%%.", bank, s.NetChange, months, s.Rate)
+	case "cutting":
+		text = fmt.Sprintf("The %s has cut rates by %.2fpp over the last %d months to %.2f%%.", bank, -s.NetChange, months, s.Rate)
+	default:
+		text = fmt.Sprintf("The %s has held rates at %.2f%% over the last %d months.", bank, s.Rate, months)
+	}
+
+	if s.Guidance != GuidanceNeutral {
+		text += fmt.Sprintf(" Latest guidance is %s.", s.Guidance)
+	}
+	// dissent: votes against the direction the latest decision took
+	if latest.Change <= 0 && latest.VotesHike > 0 {
+		text += fmt.Sprintf(" %d of %d members voted for a hike.", latest.VotesHike, latest.VotesHike+latest.VotesHold+latest.VotesCut)
+	}
+	if latest.Change >= 0 && latest.VotesCut > 0 {
+		text += fmt.Sprintf(" %d of %d members voted for a cut.", latest.VotesCut, latest.VotesHike+latest.VotesHold+latest.VotesCut)
+	}
+	if s.DaysToNextMeeting != nil {
+		text += fmt.Sprintf(" Next meeting in %.0f days.", *s.DaysToNextMeeting)
+	}
+	return text
+}
+
+// applyPolicy stores each currency's policy stance on its snapshot, dated by
+// the latest decision.
+func applyPolicy(snapshots []MacroSnapshot, stances map[string]PolicyStance) {
+	for i := range snapshots {
+		s, ok := stances[snapshots[i].Country]
+		if !ok || len(s.Decisions) == 0 {
+			continue
+		}
+		snapshots[i].setIndicator(PolicyStanceIndicator, s.Stance)
+		snapshots[i].setRelease(PolicyStanceIndicator, s.Decisions[0].DateTime)
+	}
+}
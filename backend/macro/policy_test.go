@@ -0,0 +1,98 @@
+package macro
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildPolicyStance(t *testing.T) {
+	now := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	at := func(days int) time.Time { return now.AddDate(0, 0, days) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name      string
+		decisions []PolicyDecision
+		want      PolicyStance // checked: Direction, NetChange, MoveScore, GuidanceScore, Guidance, Stance
+		nextDays  *float64
+	}{
+		{
+			// moves +0.25 at half weight and +0.25 at full weight: 0.375 / 0.75;
+			// hawkish tag and a 6-3-0 vote: 0.6 + 0.4 * 2/3; meeting in 30 days counts in full
+			name: "hiking with hawkish guidance",
+			decisions: []PolicyDecision{
+				{DateTime: at(-400), Rate: 4},
+				{DateTime: at(-180), Rate: 4.25},
+				{DateTime: at(0), Rate: 4.5, Guidance: "Hawkish", VotesHike: 6, VotesHold: 3, NextMeeting: ptr(at(30))},
+			},
+			want: PolicyStance{Direction: "hiking", NetChange: 0.5, MoveScore: 0.5, GuidanceScore: 0.867, Guidance: GuidanceHawkish,
+				Stance: 0.6*0.5 + 0.4*0.867},
+			nextDays: func() *float64 { d := 30.0; return &d }(),
+		},
+		{
+			name: "cutting without guidance",
+			decisions: []PolicyDecision{
+				{DateTime: at(-100), Rate: 5},
+				{DateTime: at(-10), Rate: 4.5},
+			},
+			want: PolicyStance{Direction: "cutting", NetChange: -0.5, MoveScore: -0.641, Guidance: GuidanceNeutral,
+				Stance: 0.6 * -0.641},
+		},
+		{
+			// the next meeting has passed without a decision, so guidance counts half
+			name: "on hold with stale dovish guidance",
+			decisions: []PolicyDecision{
+				{DateTime: at(-20), Rate: 2, Guidance: "dovish", NextMeeting: ptr(at(-1))},
+			},
+			want: PolicyStance{Direction: "on hold", GuidanceScore: -0.6, Guidance: GuidanceDovish,
+				Stance: 0.4 * 0.5 * -0.6},
+		},
+		{
+			name: "large move saturates",
+			decisions: []PolicyDecision{
+				{DateTime: at(-300), Rate: 0},
+				{DateTime: at(0), Rate: 2, Guidance: "unknown"},
+			},
+			want: PolicyStance{Direction: "hiking", NetChange: 2, MoveScore: 1, Guidance: GuidanceNeutral, Stance: 0.6},
+		},
+		{
+			name: "moves outside the window ignored",
+			decisions: []PolicyDecision{
+				{DateTime: at(-500), Rate: 1},
+				{DateTime: at(-400), Rate: 2},
+			},
+			want: PolicyStance{Direction: "on hold", Guidance: GuidanceNeutral},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := buildPolicyStance("USD", tt.decisions, PolicyOptions{AsOf: now})
+
+			if s.Direction != tt.want.Direction || s.Guidance != tt.want.Guidance {
+				t.Errorf("direction %q guidance %q, want %q %q", s.Direction, s.Guidance, tt.want.Direction, tt.want.Guidance)
+			}
+			for _, f := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"NetChange", s.NetChange, tt.want.NetChange},
+				{"MoveScore", s.MoveScore, tt.want.MoveScore},
+				{"GuidanceScore", s.GuidanceScore, tt.want.GuidanceScore},
+				{"Stance", s.Stance, tt.want.Stance},
+			} {
+				if math.Abs(f.got-f.want) > 1e-3 {
+					t.Errorf("%s = %v, want %v", f.name, f.got, f.want)
+				}
+			}
+			if (s.DaysToNextMeeting == nil) != (tt.nextDays == nil) || (s.DaysToNextMeeting != nil && *s.DaysToNextMeeting != *tt.nextDays) {
+				t.Errorf("DaysToNextMeeting = %v, want %v", s.DaysToNextMeeting, tt.nextDays)
+			}
+
+			last := tt.decisions[len(tt.decisions)-1]
+			if s.Bank != "Federal Reserve" || s.Rate != last.Rate || len(s.Decisions) != len(tt.decisions) || !s.Decisions[0].DateTime.Equal(last.DateTime) {
+				t.Errorf("stance = %+v, want the Fed at %v with decisions newest first", s, last.Rate)
+			}
+		})
+	}
+}
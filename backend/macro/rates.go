@@ -0,0 +1,82 @@
+package macro
+
+import (
+	"fmt"
+	"math"
+)
+
+// RealRateIndicator is the derived indicator key for the real policy rate,
+// interest_rate minus headline inflation_rate.
+const RealRateIndicator = "real_rate"
+
+// carryScale is the rate differential, in percentage points, that maps to a
+// ±1 pair component.
+const carryScale = 3.0
+
+// deriveRealRate sets the real rate when the snapshot has both a policy rate
+// and headline inflation, dated by the later of the two releases.
+func (m *MacroSnapshot) deriveRealRate() {
+	rate, okRate := m.Indicator("interest_rate")
+	inflation, okInfl := m.Indicator("inflation_rate")
+	if !okRate || !okInfl {
+		return
+	}
+	m.setIndicator(RealRateIndicator, round(rate-inflation, 3))
+
+	rateAt, okRateAt := m.ReleasedAt["interest_rate"]
+	inflAt, okInflAt := m.ReleasedAt["inflation_rate"]
+	if !okRateAt || !okInflAt {
+		return
+	}
+	if inflAt.After(rateAt) {
+		rateAt = inflAt
+	}
+	m.setRelease(RealRateIndicator, rateAt)
+}
+
+// rateDifferentials returns the base-minus-quote policy rate (carry) and real
+// rate differentials in percentage points, nil when either side lacks data,
+// plus the differentials scaled to [-1, 1] as pair components.
+func rateDifferentials(base, quote ScoreBreakdown) (carry, real *float64, components map[string]float64) {
+	components = make(map[string]float64, 2)
+	diff := func(key, component string) *float64 {
+		b, okB := base.RawIndicators.Indicator(key)
+		q, okQ := quote.RawIndicators.Indicator(key)
+		if !okB || !okQ {
+			return nil
+		}
+		d := round(b-q, 3)
+		components[component] = round(clamp(d/carryScale, -1, 1), 3)
+		return &d
+	}
+	carry = diff("interest_rate", "carry")
+	real = diff(RealRateIndicator, "real_rate_differential")
+	return carry, real, components
+}
+
+// explainCarry describes which side of the pair earns the carry and how the
+// real-rate differential compares. Gaps under 0.25pp count as level.
+func explainCarry(base, quote string, carry, real *float64) string {
+	text := ""
+	if carry != nil {
+		switch {
+		case math.Abs(*carry) < 0.25:
+			text += fmt.Sprintf(" Carry is roughly flat, with policy rates %.2fpp apart.", math.Abs(*carry))
+		case *carry > 0:
+			text += fmt.Sprintf(" Carry favours %s, whose policy rate is %.2fpp above %s's.", base, *carry, quote)
+		default:
+			text += fmt.Sprintf(" Carry favours %s, whose policy rate is %.2fpp above %s's.", quote, -*carry, base)
+		}
+	}
+	if real != nil {
+		switch {
+		case math.Abs(*real) < 0.25:
+			text += " Real rates are roughly level."
+		case *real > 0:
+			text += fmt.Sprintf(" After inflation, %s's real rate is %.2fpp higher.", base, *real)
+		default:
+			text += fmt.Sprintf(" After inflation, %s's real rate is %.2fpp higher.", quote, -*real)
+		}
+	}
+	return text
+}
@@ -0,0 +1,109 @@
+package macro
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeriveRealRate(t *testing.T) {
+	rateAt := time.Date(2024, 6, 12, 18, 0, 0, 0, time.UTC)
+	inflAt := time.Date(2024, 6, 12, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		rate, infl  *float64
+		releases    map[string]time.Time
+		want        *float64
+		wantRelease time.Time
+	}{
+		{"positive", ptr(5.5), ptr(3.3), nil, ptr(2.2), time.Time{}},
+		{"negative", ptr(0.1), ptr(2.8), nil, ptr(-2.7), time.Time{}},
+		{"dated by the later release", ptr(5.5), ptr(3.3), map[string]time.Time{"interest_rate": rateAt, "inflation_rate": inflAt}, ptr(2.2), rateAt},
+		{"undated when one release time is unknown", ptr(5.5), ptr(3.3), map[string]time.Time{"inflation_rate": inflAt}, ptr(2.2), time.Time{}},
+		{"no inflation", ptr(5.5), nil, nil, nil, time.Time{}},
+		{"no rate", nil, ptr(3.3), nil, nil, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := MacroSnapshot{Country: "USD", InterestRate: tt.rate, InflationRate: tt.infl, ReleasedAt: tt.releases}
+			m.deriveRealRate()
+			if (m.RealRate == nil) != (tt.want == nil) || (m.RealRate != nil && math.Abs(*m.RealRate-*tt.want) > eps) {
+				t.Fatalf("real rate = %v, want %v", m.RealRate, tt.want)
+			}
+			if got := m.ReleasedAt[RealRateIndicator]; !got.Equal(tt.wantRelease) {
+				t.Errorf("real rate released %v, want %v", got, tt.wantRelease)
+			}
+		})
+	}
+}
+
+func TestPairRateDifferentials(t *testing.T) {
+	snapshot := func(code string, rate, infl *float64) ScoreBreakdown {
+		m := MacroSnapshot{Country: code, InterestRate: rate, InflationRate: infl}
+		m.deriveRealRate()
+		return ScoreBreakdown{Country: code, RawIndicators: m}
+	}
+	scores := map[string]ScoreBreakdown{
+		"EUR": snapshot("EUR", ptr(4), ptr(2.6)),   // real 1.4
+		"USD": snapshot("USD", ptr(5.5), ptr(3.3)), // real 2.2
+		"JPY": snapshot("JPY", ptr(0.1), ptr(2.8)), // real -2.7
+		"CHF": snapshot("CHF", ptr(1.25), nil),     // no real rate
+	}
+
+	tests := []struct {
+		base, quote         string
+		carry, real         *float64
+		carryComp, realComp *float64
+		explanationMentions string
+	}{
+		{"EUR", "USD", ptr(-1.5), ptr(-0.8), ptr(-0.5), ptr(-0.267), "Carry favours USD"},
+		{"USD", "EUR", ptr(1.5), ptr(0.8), ptr(0.5), ptr(0.267), "Carry favours USD"},
+		{"USD", "JPY", ptr(5.4), ptr(4.9), ptr(1), ptr(1), "Carry favours USD"}, // saturated
+		{"CHF", "JPY", ptr(1.15), nil, ptr(0.383), nil, "Carry favours CHF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.base+tt.quote, func(t *testing.T) {
+			p, err := PairSentimentFromScores(scores, tt.base, tt.quote)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range []struct {
+				name      string
+				got, want *float64
+			}{
+				{"carry", p.Carry, tt.carry},
+				{"real rate differential", p.RealRateDifferential, tt.real},
+				{"carry component", component(p.Components, "carry"), tt.carryComp},
+				{"real rate differential component", component(p.Components, "real_rate_differential"), tt.realComp},
+			} {
+				if (f.got == nil) != (f.want == nil) || (f.got != nil && math.Abs(*f.got-*f.want) > eps) {
+					t.Errorf("%s = %v, want %v", f.name, deref(f.got), deref(f.want))
+				}
+			}
+			if p.Carry != nil && math.Signbit(*p.Carry) != math.Signbit(*scores[tt.base].RawIndicators.InterestRate-*scores[tt.quote].RawIndicators.InterestRate) {
+				t.Errorf("carry %v does not follow base minus quote", *p.Carry)
+			}
+			if !strings.Contains(p.Explanation, tt.explanationMentions) {
+				t.Errorf("explanation %q does not mention %q", p.Explanation, tt.explanationMentions)
+			}
+		})
+	}
+}
+
+func ptr(v float64) *float64 { return &v }
+
+func deref(v *float64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func component(comps map[string]float64, key string) *float64 {
+	if v, ok := comps[key]; ok {
+		return &v
+	}
+	return nil
+}
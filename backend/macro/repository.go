@@ -0,0 +1,289 @@
+package macro
+
+import (
+	"context"
+	"economic_indicator/models"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+
+	"github.com/uptrace/bun"
+)
+
+// ErrNoSnapshots is returned when no indicator data is available for the request.
+var ErrNoSnapshots = errors.New("no indicator data available")
+
+// SnapshotRepository builds macro snapshots from the econ_indicators table,
+// falling back to a JSON snapshot file when the table has no usable rows.
+type SnapshotRepository struct {
+	DB           *bun.DB
+	FallbackPath string
+}
+
+// NewSnapshotRepository NewSnapshotRepository
+func NewSnapshotRepository(db *bun.DB, fallbackPath string) *SnapshotRepository {
+	return &SnapshotRepository{DB: db, FallbackPath: fallbackPath}
+}
+
+// Load returns one snapshot per currency assembled from the latest indicator rows.
+// The JSON file is only used without a database or when the table has no usable
+// rows; query errors are returned, never masked by the file. Point-in-time
+// requests never fall back to the JSON file, which has no dates.
+func (r *SnapshotRepository) Load(ctx context.Context, opts ScoreOptions) ([]MacroSnapshot, error) {
+	if r.DB != nil {
+		snapshots, err := r.loadFromDB(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(snapshots) > 0 {
+			return snapshots, nil
+		}
+	}
+
+	if r.FallbackPath == "" || opts.PointInTime() {
+		return nil, ErrNoSnapshots
+	}
+
+	return LoadSnapshots(r.FallbackPath)
+}
+
+func (r *SnapshotRepository) loadFromDB(ctx context.Context, opts ScoreOptions) ([]MacroSnapshot, error) {
+	// latest release per (country, category) visible at opts.AsOf
+	latest := r.DB.NewSelect().
+		Model((*models.EconIndicator)(nil)).
+		Column("country", "category").
+		ColumnExpr("MAX(datetime)").
+		Where("value IS NOT NULL").
+		Apply(asOfFilter(opts)).
+		Group("country", "category")
+
+	var rows []models.EconIndicator
+	err := r.DB.NewSelect().
+		Model(&rows).
+		Column("country", "category", "value", "datetime").
+		Where("value IS NOT NULL").
+		Where("(country, category, datetime) IN (?)", latest).
+		Apply(asOfFilter(opts)).
+		Order("datetime DESC", "revision DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("select econ indicators: %w", err)
+	}
+
+	return snapshotsFromRows(rows), nil
+}
+
+// History returns the release values per currency and indicator, oldest first,
+// restricted to what was known at opts.AsOf: the last opts.Model.historyDepth
+// releases, taken from the provider of the indicator's latest release so
+// overlapping feeds of the same series are not interleaved. A revised release
+// contributes its latest revision known then.
+func (r *SnapshotRepository) History(ctx context.Context, opts ScoreOptions) (IndicatorHistory, error) {
+	if r.DB == nil {
+		return nil, fmt.Errorf("no database configured")
+	}
+	model := opts.Model
+	if model == nil {
+		model = DefaultScoringModel()
+	}
+
+	// revisions of a release share its rank
+	ranked := r.DB.NewSelect().
+		Model((*models.EconIndicator)(nil)).
+		Column("country", "category", "source", "value", "datetime", "revision").
+		ColumnExpr("DENSE_RANK() OVER (PARTITION BY country, category, source ORDER BY datetime DESC) AS release_rank").
+		Where("value IS NOT NULL").
+		Apply(asOfFilter(opts))
+
+	var rows []models.EconIndicator
+	err := r.DB.NewSelect().
+		Model(&rows).
+		ModelTableExpr("(?) AS econ_indicator", ranked).
+		Column("country", "category", "source", "value", "datetime").
+		Where("release_rank <= ?", model.historyDepth(opts.Normalization)).
+		Order("datetime DESC", "revision DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("select indicator history: %w", err)
+	}
+
+	out := make(IndicatorHistory)
+	sources := make(map[string]string)
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		series := row.Country + "|" + row.Category
+		source, ok := sources[series]
+		if !ok {
+			source = row.Source
+			sources[series] = source
+		}
+		key := series + "|" + row.DateTime.String()
+		if row.Source != source || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		code, ok := CurrencyForCountry(row.Country)
+		if !ok {
+			continue
+		}
+		def, ok := LookupIndicator(row.Category)
+		if !ok || def.Derived {
+			continue
+		}
+		if out[code] == nil {
+			out[code] = make(map[string][]float64)
+		}
+		out[code][def.Key] = append(out[code][def.Key], *row.Value)
+	}
+
+	for _, byKey := range out {
+		for _, values := range byKey {
+			slices.Reverse(values)
+		}
+	}
+	return out, nil
+}
+
+// Scores loads snapshots and scores them, fetching release history first when
+// opts asks for history normalisation or the model has momentum rules. With a
+// database, each score carries its currency's next high-impact calendar event
+// when the calendar can be read.
+func (r *SnapshotRepository) Scores(ctx context.Context, opts ScoreOptions) (map[string]ScoreBreakdown, error) {
+	snapshots, err := r.Load(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return r.scoreSnapshots(ctx, snapshots, opts)
+}
+
+// scoreSnapshots adds the derived indicators the model needs to already loaded
+// snapshots, in place, and scores them. A derived indicator whose source can't
+// be read is logged and left missing, like any indicator without data.
+func (r *SnapshotRepository) scoreSnapshots(ctx context.Context, snapshots []MacroSnapshot, opts ScoreOptions) (map[string]ScoreBreakdown, error) {
+	var err error
+	model := opts.Model
+	if model == nil {
+		model = DefaultScoringModel()
+	}
+
+	if r.DB != nil && model.usesIndicator(SurpriseIndicator) {
+		surprises, err := r.Surprises(ctx, SurpriseOptions{AsOf: opts.AsOf, ReleaseTimeOnly: opts.ReleaseTimeOnly})
+		if err != nil {
+			log.Printf("surprise index: %v", err)
+		} else {
+			applySurprises(snapshots, surprises)
+		}
+	}
+
+	if r.DB != nil && model.usesIndicator(PolicyStanceIndicator) {
+		stances, err := r.PolicyStances(ctx, PolicyOptions{AsOf: opts.AsOf, ReleaseTimeOnly: opts.ReleaseTimeOnly})
+		if err != nil {
+			log.Printf("policy stances: %v", err)
+		} else {
+			applyPolicy(snapshots, stances)
+		}
+	}
+
+	needHistory := opts.Normalization == NormalizationHistory || (r.DB != nil && model.usesMomentum())
+	if needHistory && opts.History == nil {
+		if opts.History, err = r.History(ctx, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	scores := BuildScoresByCountry(snapshots, opts)
+	if r.DB != nil {
+		// the next event only annotates the scores; don't fail scoring over it
+		if next, err := r.NextHighImpactEvents(ctx, opts); err != nil {
+			log.Printf("next high-impact events: %v", err)
+		} else {
+			applyNextEvents(scores, next)
+		}
+	}
+	return scores, nil
+}
+
+// asOfFilter restricts econ_indicators rows to those known at opts.AsOf.
+func asOfFilter(opts ScoreOptions) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		if !opts.PointInTime() {
+			return q
+		}
+		q = q.Where("datetime <= ?", opts.AsOf.UTC())
+		if !opts.ReleaseTimeOnly {
+			q = q.Where("ingested_at <= ?", opts.AsOf.UTC())
+		}
+		return q
+	}
+}
+
+// snapshotsFromRows keeps the first row seen per (currency, category), so rows
+// must be ordered newest first.
+func snapshotsFromRows(rows []models.EconIndicator) []MacroSnapshot {
+	byCurrency := make(map[string]*MacroSnapshot)
+	seen := make(map[string]bool)
+
+	for _, row := range rows {
+		code, ok := CurrencyForCountry(row.Country)
+		if !ok || row.Value == nil {
+			continue
+		}
+
+		def, ok := LookupIndicator(row.Category)
+		if !ok || def.Derived {
+			continue
+		}
+		key := code + "|" + def.Key
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		snap, ok := byCurrency[code]
+		if !ok {
+			snap = &MacroSnapshot{Country: code}
+			byCurrency[code] = snap
+		}
+		snap.setIndicator(def.Key, *row.Value)
+		snap.setRelease(def.Key, row.DateTime)
+	}
+
+	out := make([]MacroSnapshot, 0, len(byCurrency))
+	for _, snap := range byCurrency {
+		snap.deriveRealRate()
+		out = append(out, *snap)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Country < out[j].Country })
+
+	return out
+}
+
+// UnmappedCategories counts stored rows per category that the indicator
+// registry does not recognise; those rows are never scored.
+func (r *SnapshotRepository) UnmappedCategories(ctx context.Context) ([]CategoryCount, error) {
+	if r.DB == nil {
+		return nil, fmt.Errorf("no database configured")
+	}
+
+	var counts []CategoryCount
+	err := r.DB.NewSelect().
+		Model((*models.EconIndicator)(nil)).
+		Column("category").
+		ColumnExpr("COUNT(*) AS count").
+		Group("category").
+		Scan(ctx, &counts)
+	if err != nil {
+		return nil, fmt.Errorf("select indicator categories: %w", err)
+	}
+
+	unmapped := make(UnmappedCategories)
+	for _, c := range counts {
+		if _, ok := LookupIndicator(c.Category); !ok {
+			unmapped[c.Category] += c.Count
+		}
+	}
+	return unmapped.Sorted(), nil
+}
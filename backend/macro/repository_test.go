@@ -0,0 +1,173 @@
+package macro
+
+import (
+	"context"
+	"database/sql"
+	"economic_indicator/models"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+)
+
+// unreachableDB is a database whose every query fails.
+func unreachableDB(t *testing.T) *bun.DB {
+	t.Helper()
+	sqldb, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/none?timeout=1s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := bun.NewDB(sqldb, mysqldialect.New())
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestScoreSnapshotsWithoutEnrichments(t *testing.T) {
+	levels := &ScoringModel{Name: "levels", Rules: []IndicatorRule{
+		{Component: "interest_rate", Indicator: "interest_rate", Transform: TransformLinear, Scale: 5},
+		{Component: "policy", Indicator: PolicyStanceIndicator, Transform: TransformLinear, Scale: 1},
+		{Component: "surprise", Indicator: SurpriseIndicator, Transform: TransformLinear, Scale: 1},
+	}}
+	if err := levels.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	snapshots := []MacroSnapshot{{Country: "USD"}, {Country: "JPY"}}
+	snapshots[0].setIndicator("interest_rate", 5)
+	snapshots[1].setIndicator("interest_rate", 0.5)
+
+	repo := NewSnapshotRepository(unreachableDB(t), "")
+	scores, err := repo.scoreSnapshots(context.Background(), snapshots, ScoreOptions{Model: levels})
+	if err != nil {
+		t.Fatalf("scoring failed with the calendar unavailable: %v", err)
+	}
+	if len(scores) != 2 || scores["USD"].Components["interest_rate"] != 1 {
+		t.Fatalf("scores = %+v", scores)
+	}
+	for _, c := range []string{"policy", "surprise"} {
+		if _, ok := scores["USD"].Components[c]; ok {
+			t.Errorf("%s component scored without its source data", c)
+		}
+	}
+	if scores["USD"].NextHighImpactEvent != nil {
+		t.Errorf("NextEvent = %+v, want none", scores["USD"].NextHighImpactEvent)
+	}
+}
+
+// queryRecorder keeps the SQL of every query run on a DB.
+type queryRecorder struct {
+	queries []string
+}
+
+func (h *queryRecorder) BeforeQuery(ctx context.Context, ev *bun.QueryEvent) context.Context {
+	h.queries = append(h.queries, ev.Query)
+	return ctx
+}
+
+func (h *queryRecorder) AfterQuery(context.Context, *bun.QueryEvent) {}
+
+var cutoffPattern = regexp.MustCompile(`(\w+) <= '([^']+)'`)
+
+// cutoffs reads the "column <= 'time'" conditions of a query.
+func cutoffs(t *testing.T, query string) map[string][]time.Time {
+	t.Helper()
+	out := make(map[string][]time.Time)
+	for _, m := range cutoffPattern.FindAllStringSubmatch(query, -1) {
+		at, err := time.Parse("2006-01-02 15:04:05.999999", m[2])
+		if err != nil {
+			t.Fatalf("cut-off %s: %v", m[0], err)
+		}
+		out[m[1]] = append(out[m[1]], at)
+	}
+	return out
+}
+
+func TestAsOfFilter(t *testing.T) {
+	db := unreachableDB(t)
+	asOf := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 8, 30, 0, 0, time.UTC) }
+
+	releases := []struct {
+		name                 string
+		datetime, ingestedAt time.Time
+	}{
+		{"released and ingested before", day(28), day(28)},
+		{"at the cut-off", asOf, asOf},
+		{"ingested late", day(28), day(31)},
+		{"released after", day(31), day(31)},
+	}
+	tests := []struct {
+		name      string
+		opts      ScoreOptions
+		wantKnown []bool // per release
+	}{
+		{"latest data", ScoreOptions{}, []bool{true, true, true, true}},
+		{"as of", ScoreOptions{AsOf: asOf}, []bool{true, true, false, false}},
+		{"release time only", ScoreOptions{AsOf: asOf, ReleaseTimeOnly: true}, []bool{true, true, true, false}},
+		{"cut-off in another zone", ScoreOptions{AsOf: asOf.In(time.FixedZone("EST", -5*3600))}, []bool{true, true, false, false}},
+	}
+	for _, tt := range tests {
+		for table, model := range map[string]any{"econ_indicators": (*models.EconIndicator)(nil), "policy_decisions": (*models.PolicyDecision)(nil)} {
+			t.Run(tt.name+" "+table, func(t *testing.T) {
+				cut := cutoffs(t, db.NewSelect().Model(model).Apply(asOfFilter(tt.opts)).String())
+				for i, rel := range releases {
+					known := true
+					for col, values := range map[string]time.Time{"datetime": rel.datetime, "ingested_at": rel.ingestedAt} {
+						for _, c := range cut[col] {
+							known = known && !values.After(c)
+						}
+					}
+					if known != tt.wantKnown[i] {
+						t.Errorf("%s: known = %v, want %v (cut-offs %v)", rel.name, known, tt.wantKnown[i], cut)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestAsOfFilterReuse(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	for _, releaseTimeOnly := range []bool{false, true} {
+		db := unreachableDB(t)
+		rec := &queryRecorder{}
+		db.AddQueryHook(rec)
+		r := NewSnapshotRepository(db, "")
+		ctx := context.Background()
+		opts := ScoreOptions{AsOf: asOf, ReleaseTimeOnly: releaseTimeOnly}
+
+		// every query fails, but not before the hook has seen it
+		r.Load(ctx, opts)
+		r.History(ctx, opts)
+		r.Surprises(ctx, SurpriseOptions{AsOf: asOf, ReleaseTimeOnly: releaseTimeOnly})
+		r.PolicyStances(ctx, PolicyOptions{AsOf: asOf, ReleaseTimeOnly: releaseTimeOnly})
+
+		tables := []string{"econ_indicators", "econ_indicators", "econ_indicators", "policy_decisions"}
+		filters := []int{2, 1, 1, 1} // snapshots filter the latest-release subquery too
+		if len(rec.queries) != len(tables) {
+			t.Fatalf("ran %d queries, want %d: %v", len(rec.queries), len(tables), rec.queries)
+		}
+		for i, q := range rec.queries {
+			if !strings.Contains(q, "`"+tables[i]+"`") {
+				t.Errorf("query %d does not read %s: %s", i, tables[i], q)
+			}
+			cut := cutoffs(t, q)
+			wantIngested := filters[i]
+			if releaseTimeOnly {
+				wantIngested = 0
+			}
+			if len(cut["datetime"]) != filters[i] || len(cut["ingested_at"]) != wantIngested {
+				t.Errorf("query %d (release time only %v) cut-offs = %v, want %d on datetime and %d on ingested_at: %s",
+					i, releaseTimeOnly, cut, filters[i], wantIngested, q)
+			}
+			for _, at := range append(cut["datetime"], cut["ingested_at"]...) {
+				if !at.Equal(asOf) {
+					t.Errorf("query %d cut-off %v, want %v", i, at, asOf)
+				}
+			}
+		}
+	}
+}
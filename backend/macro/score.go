@@ -8,77 +8,174 @@ import (
 
 // ScoreBreakdown ScoreBreakdown
 type ScoreBreakdown struct {
-	Country       string             `json:"country"`
-	TotalScore    float64            `json:"total_score"`
-	Components    map[string]float64 `json:"components"`
-	RawIndicators MacroSnapshot      `json:"raw_indicators"`
-	Explanation   string             `json:"explanation"`
+	Country             string             `json:"country"`
+	Model               string             `json:"model"`
+	Normalization       string             `json:"normalization"`
+	TotalScore          float64            `json:"total_score"`
+	Components          map[string]float64 `json:"components"`
+	Parameters          *CountryParams     `json:"parameters,omitempty"`
+	Coverage            float64            `json:"coverage"` // share of the model's rule weight whose indicator has data
+	ComponentAges       map[string]float64 `json:"component_age_days,omitempty"`
+	ComponentWeights    map[string]float64 `json:"component_weights,omitempty"` // effective weights when decay is on
+	StaleComponents     []string           `json:"stale_components,omitempty"`
+	MissingIndicators   []string           `json:"missing_indicators,omitempty"`
+	LowConfidence       bool               `json:"low_confidence"` // Coverage below ScoreOptions.MinCoverage
+	NextHighImpactEvent *CalendarEvent     `json:"next_high_impact_event,omitempty"`
+	RawIndicators       MacroSnapshot      `json:"raw_indicators"`
+	Explanation         string             `json:"explanation"`
 }
 
-// ScoreSnapshot ScoreSnapshot
-func ScoreSnapshot(m MacroSnapshot) ScoreBreakdown {
-	components := make(map[string]float64)
+// ScoreSnapshot scores a snapshot with the given model (nil selects the default model)
+// using absolute normalisation.
+func ScoreSnapshot(m MacroSnapshot, model *ScoringModel) ScoreBreakdown {
+	return scoreAbsolute(m, model, ScoreOptions{})
+}
+
+func scoreAbsolute(m MacroSnapshot, model *ScoringModel, opts ScoreOptions) ScoreBreakdown {
+	if model == nil {
+		model = DefaultScoringModel()
+	}
+
+	raw := rawComponents(m, model, opts.History[m.Country])
+	components := make(map[string]float64, len(raw))
+	for _, rule := range model.Rules {
+		if v, ok := raw[rule.Component]; ok {
+			components[rule.Component] = clamp(v, rule.Min, rule.Max)
+		}
+	}
 
-	components["gdp_growth"] = clamp(m.GDPAnnualGrowthRate/4.0, -1, 1)       // -4%→-1, 0→0, 4%→1
-	components["unemployment"] = clamp((10.0-m.UnemploymentRate)/6.0, -1, 1) // 4%→~1, 10%→0, >10%→neg
-	components["inflation"] = scoreInflation(m.InflationRate)
-	components["interest_rate"] = clamp(m.InterestRate/10.0, -1, 1) // up to 10% considered max positive
-	// Temporarily disabled: values are in local currency units (not comparable across countries)
-	// Once macro.json uses % of GDP, we can re-enable with proper scaling.
-	//
-	// components["current_account"] = clamp(m.CurrentAccount/5.0, -1, 1)
-	// components["balance_of_trade"] = clamp(m.BalanceOfTrade/5.0, -1, 1)
+	return newBreakdown(m, model, NormalizationAbsolute, components, opts)
+}
 
-	components["business_confidence"] = clamp(m.BusinessConfidence/100.0, -1, 1)
-	components["manufacturing_pmi"] = scorePMI(m.ManufacturingPMI)
+// rawComponents returns each rule's signed, unclamped transform for the snapshot,
+// anchored on the currency's parameters. Momentum rules read the currency's release
+// history. Rules whose indicator (or history) is missing are skipped.
+func rawComponents(m MacroSnapshot, model *ScoringModel, history map[string][]float64) map[string]float64 {
+	params, hasParams := model.paramsFor(m.Country)
 
-	if spmi := m.ParsedServicesPMI(); spmi != nil {
-		components["services_pmi"] = scorePMI(*spmi)
+	raw := make(map[string]float64, len(model.Rules))
+	for _, rule := range model.Rules {
+		if rule.IsMomentum() {
+			if v, ok := rule.anchored(params, hasParams).momentum(history[rule.Indicator]); ok {
+				raw[rule.Component] = v
+			}
+			continue
+		}
+		v, ok := m.Indicator(rule.Indicator)
+		if !ok {
+			continue
+		}
+		raw[rule.Component] = rule.anchored(params, hasParams).raw(v)
 	}
+	return raw
+}
 
-	components["consumer_confidence"] = clamp(m.ConsumerConfidence/100.0, -1, 1)
-	components["retail_sales_mom"] = clamp(m.RetailSalesMoM/2.0, -1, 1) // ±2% saturates
+// newBreakdown aggregates finished components into the weighted total and explanation,
+// down-weighting stale components when opts asks for decay.
+func newBreakdown(
+	m MacroSnapshot,
+	model *ScoringModel,
+	normalization string,
+	components map[string]float64,
+	opts ScoreOptions,
+) ScoreBreakdown {
+	ages, stale := componentAges(m, model, components, opts.referenceTime())
 
-	// average all non-zero components
+	weights := make(map[string]float64, len(model.Rules))
+	for _, rule := range model.Rules {
+		weights[rule.Component] = rule.Weight
+	}
+	var effective map[string]float64
+	if opts.DecayHalfLife > 0 {
+		effective = make(map[string]float64, len(components))
+		for k := range components {
+			if age, ok := ages[k]; ok {
+				weights[k] *= decayFactor(age, opts.DecayHalfLife)
+			}
+			effective[k] = round(weights[k], 3)
+		}
+	}
+
+	// weighted average of all components, skipping NaNs
 	var sum float64
-	var count float64
-	for _, v := range components {
-		// we include even negative ones, only skip NaNs
+	var weightSum float64
+	for k, v := range components {
 		if !math.IsNaN(v) {
-			sum += v
-			count++
+			sum += v * weights[k]
+			weightSum += weights[k]
 		}
 	}
 
 	total := 0.0
-	if count > 0 {
-		total = sum / count
+	if weightSum > 0 {
+		total = sum / weightSum
 	}
 
 	total = round(total, 3)
 	components = roundMap(components, 3)
 
-	explanation := explainMacroSnapshot(m, components, total)
+	levels, momentum := splitMomentum(model, components)
+	explanation := explainMacroSnapshot(m, levels, total)
+	explanation += explainMomentum(model, momentum)
+	explanation += explainStale(model, stale, ages)
+
+	var parameters *CountryParams
+	if params, ok := model.paramsFor(m.Country); ok {
+		parameters = &params
+	}
+
+	coverage, missing := dataCoverage(m, model)
 
 	return ScoreBreakdown{
-		Country:       m.Country,
-		TotalScore:    total,
-		Components:    components,
-		RawIndicators: m,
-		Explanation:   explanation,
+		Country:           m.Country,
+		Model:             model.Name,
+		Normalization:     normalization,
+		TotalScore:        total,
+		Components:        components,
+		Parameters:        parameters,
+		Coverage:          coverage,
+		ComponentAges:     ages,
+		ComponentWeights:  effective,
+		StaleComponents:   stale,
+		MissingIndicators: missing,
+		RawIndicators:     m,
+		Explanation:       explanation,
 	}
 }
 
-func scoreInflation(inflation float64) float64 {
-	// 2% is ideal inflation target; penalize large deviations
-	diff := inflation - 2.0
-	// ±6% around target → [-1,1]
-	return clamp(-diff/6.0, -1, 1)
+// dataCoverage returns the share of level-rule weight whose indicator the snapshot
+// carries, and the indicator keys it is missing. Momentum rules reuse the same
+// indicators and are left out.
+func dataCoverage(m MacroSnapshot, model *ScoringModel) (float64, []string) {
+	var covered, total float64
+	var missing []string
+	for _, rule := range model.Rules {
+		if rule.IsMomentum() {
+			continue
+		}
+		total += rule.Weight
+		if _, ok := m.Indicator(rule.Indicator); ok {
+			covered += rule.Weight
+		} else {
+			missing = append(missing, rule.Indicator)
+		}
+	}
+	if total == 0 {
+		return 0, missing
+	}
+	return round(covered/total, 3), missing
 }
 
-func scorePMI(pmi float64) float64 {
-	// PMI 50 = neutral, 60 = strong expansion, 40 = strong contraction
-	return clamp((pmi-50.0)/10.0, -1, 1)
+// flagLowConfidence marks scores whose coverage is below minCoverage.
+func flagLowConfidence(scores map[string]ScoreBreakdown, minCoverage float64) {
+	for code, s := range scores {
+		if s.Coverage >= minCoverage {
+			continue
+		}
+		s.LowConfidence = true
+		s.Explanation += fmt.Sprintf(" Low confidence: only %.0f%% of the model's indicators have data.", s.Coverage*100)
+		scores[code] = s
+	}
 }
 
 func clamp(v, min, max float64) float64 {
@@ -142,6 +239,8 @@ func explainMacroSnapshot(m MacroSnapshot, comps map[string]float64, total float
 			return "inflation near target"
 		case "interest_rate":
 			return "interest rate level"
+		case "real_rate":
+			return "real interest rate"
 		case "current_account":
 			return "current account balance"
 		case "balance_of_trade":
@@ -156,6 +255,8 @@ func explainMacroSnapshot(m MacroSnapshot, comps map[string]float64, total float
 			return "consumer confidence"
 		case "retail_sales_mom":
 			return "retail sales momentum"
+		case "policy_stance":
+			return "central bank policy stance"
 		default:
 			return key
 		}
@@ -0,0 +1,79 @@
+package macro
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// staleAfter is how old a release can get before the next one is overdue,
+// based on the indicator's publication frequency.
+func staleAfter(frequency string) time.Duration {
+	switch frequency {
+	case "monthly":
+		return 60 * day
+	case "quarterly":
+		return 135 * day
+	case "per meeting":
+		return 120 * day
+	}
+	return 90 * day
+}
+
+// componentAges returns the age in days of each scored component's release and
+// the components whose release is older than its frequency allows. Components
+// whose release time is unknown (e.g. the JSON snapshot) have no age.
+func componentAges(m MacroSnapshot, model *ScoringModel, components map[string]float64, now time.Time) (map[string]float64, []string) {
+	if len(m.ReleasedAt) == 0 {
+		return nil, nil
+	}
+
+	ages := make(map[string]float64, len(components))
+	var stale []string
+	for _, rule := range model.Rules {
+		if _, ok := components[rule.Component]; !ok {
+			continue
+		}
+		released, ok := m.ReleasedAt[rule.Indicator]
+		if !ok {
+			continue
+		}
+		age := now.Sub(released)
+		if age < 0 {
+			age = 0
+		}
+		ages[rule.Component] = round(age.Hours()/24, 1)
+
+		if def, ok := LookupIndicator(rule.Indicator); ok && age > staleAfter(def.Frequency) {
+			stale = append(stale, rule.Component)
+		}
+	}
+	sort.Strings(stale)
+	return ages, stale
+}
+
+// decayFactor halves for every halfLife of age.
+func decayFactor(ageDays float64, halfLife time.Duration) float64 {
+	return math.Pow(0.5, ageDays*float64(day)/float64(halfLife))
+}
+
+func explainStale(model *ScoringModel, stale []string, ages map[string]float64) string {
+	if len(stale) == 0 {
+		return ""
+	}
+	labels := make(map[string]string, len(model.Rules))
+	for _, rule := range model.Rules {
+		if def, ok := LookupIndicator(rule.Indicator); ok {
+			labels[rule.Component] = def.Label
+		}
+	}
+
+	parts := make([]string, len(stale))
+	for i, k := range stale {
+		parts[i] = fmt.Sprintf("%s (%.0f days old)", labels[k], ages[k])
+	}
+	return " Outdated inputs: " + joinWithAnd(parts) + "."
+}
@@ -0,0 +1,90 @@
+package macro
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestComponentAges(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	ago := func(days float64) time.Time { return now.Add(-time.Duration(days * float64(day))) }
+
+	model := DefaultScoringModel()
+	m := MacroSnapshot{Country: "USD", ReleasedAt: map[string]time.Time{
+		"inflation_rate":         ago(61),   // monthly, overdue after 60 days
+		"unemployment_rate":      ago(59.5), // monthly, still current
+		"interest_rate":          ago(121),  // per meeting, overdue after 120 days
+		"gdp_annual_growth_rate": ago(100),  // quarterly, current until 135 days
+		"manufacturing_pmi":      now.Add(time.Hour),
+		"services_pmi":           ago(400), // not scored, so no age
+	}}
+	components := map[string]float64{"inflation": 0, "unemployment": 0, "interest_rate": 0, "gdp_growth": 0, "manufacturing_pmi": 0, "business_confidence": 0}
+
+	ages, stale := componentAges(m, model, components, now)
+	want := map[string]float64{"inflation": 61, "unemployment": 59.5, "interest_rate": 121, "gdp_growth": 100, "manufacturing_pmi": 0}
+	if len(ages) != len(want) {
+		t.Fatalf("ages = %v, want %v", ages, want)
+	}
+	for k, v := range want {
+		if got, ok := ages[k]; !ok || got != v {
+			t.Errorf("%s age = %v, want %v", k, got, v)
+		}
+	}
+	if !slices.Equal(stale, []string{"inflation", "interest_rate"}) {
+		t.Errorf("stale = %v, want inflation and interest_rate", stale)
+	}
+
+	if ages, stale := componentAges(MacroSnapshot{Country: "USD"}, model, components, now); ages != nil || stale != nil {
+		t.Errorf("without release times: ages %v, stale %v", ages, stale)
+	}
+}
+
+func TestDecayFactor(t *testing.T) {
+	halfLife := 30 * day
+	tests := []struct {
+		ageDays float64
+		want    float64
+	}{
+		{0, 1},
+		{15, math.Sqrt(0.5)},
+		{30, 0.5},
+		{60, 0.25},
+		{90, 0.125},
+	}
+	for _, tt := range tests {
+		if got := decayFactor(tt.ageDays, halfLife); math.Abs(got-tt.want) > eps {
+			t.Errorf("decayFactor(%v days) = %v, want %v", tt.ageDays, got, tt.want)
+		}
+	}
+}
+
+func TestDecayHalfLifeWeights(t *testing.T) {
+	model := &ScoringModel{Name: "decay", Rules: []IndicatorRule{
+		{Component: "rates", Indicator: "interest_rate", Transform: TransformLinear, Scale: 10},
+		{Component: "jobs", Indicator: "unemployment_rate", Transform: TransformLinear, Scale: 10},
+	}}
+	if err := model.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	m := MacroSnapshot{Country: "USD"}
+	m.setIndicator("interest_rate", 8)     // component 0.8, released today
+	m.setIndicator("unemployment_rate", 2) // component 0.2, one half-life old
+	m.setRelease("interest_rate", asOf)
+	m.setRelease("unemployment_rate", asOf.Add(-45*day))
+
+	plain := scoreAbsolute(m, model, ScoreOptions{AsOf: asOf})
+	if plain.TotalScore != 0.5 || plain.ComponentWeights != nil {
+		t.Errorf("without decay: total %v, weights %v; want 0.5 and no effective weights", plain.TotalScore, plain.ComponentWeights)
+	}
+
+	decayed := scoreAbsolute(m, model, ScoreOptions{AsOf: asOf, DecayHalfLife: 45 * day})
+	if w := decayed.ComponentWeights; w["rates"] != 1 || w["jobs"] != 0.5 {
+		t.Errorf("effective weights = %v, want rates 1 and jobs 0.5", w)
+	}
+	if want := round((0.8+0.5*0.2)/1.5, 3); decayed.TotalScore != want {
+		t.Errorf("decayed total = %v, want %v", decayed.TotalScore, want)
+	}
+}
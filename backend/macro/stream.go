@@ -0,0 +1,176 @@
+package macro
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EventRelease is the EngineEvent type of a new indicator release; score
+// events use the Change* kinds as their type.
+const EventRelease = "release"
+
+// DefaultSubscriberBuffer is how many events a subscriber may fall behind
+// before further events are dropped for it.
+const DefaultSubscriberBuffer = 64
+
+// EngineEvent is a score change or indicator release pushed to subscribers.
+type EngineEvent struct {
+	Type       string            `json:"type"` // EventRelease or one of the Change* kinds
+	TS         time.Time         `json:"ts"`
+	Currencies []string          `json:"currencies"` // currencies the event concerns
+	Symbol     string            `json:"symbol,omitempty"`
+	Score      *ScoreChange      `json:"score,omitempty"`
+	Release    *IndicatorRelease `json:"release,omitempty"`
+}
+
+// IndicatorRelease is a new reading the engine picked up for a currency.
+type IndicatorRelease struct {
+	Currency   string     `json:"currency"`
+	Indicator  string     `json:"indicator"` // registry key
+	Label      string     `json:"label"`
+	Value      float64    `json:"value"`
+	Previous   *float64   `json:"previous"` // the reading it replaced, nil for a first reading
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+}
+
+// subscribers fans engine events out to Subscribe callers.
+type subscribers struct {
+	mu   sync.Mutex
+	subs map[chan EngineEvent]struct{}
+}
+
+// Subscribe returns a channel receiving every event the engine publishes from
+// now on, and a func that unsubscribes and closes the channel. A subscriber
+// more than buffer events behind misses events rather than stalling the engine.
+func (e *Engine) Subscribe(buffer int) (<-chan EngineEvent, func()) {
+	if buffer <= 0 {
+		buffer = DefaultSubscriberBuffer
+	}
+	ch := make(chan EngineEvent, buffer)
+
+	e.subscribers.mu.Lock()
+	if e.subscribers.subs == nil {
+		e.subscribers.subs = make(map[chan EngineEvent]struct{})
+	}
+	e.subscribers.subs[ch] = struct{}{}
+	e.subscribers.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			e.subscribers.mu.Lock()
+			delete(e.subscribers.subs, ch)
+			close(ch)
+			e.subscribers.mu.Unlock()
+		})
+	}
+}
+
+// publish hands events to every subscriber without blocking.
+func (e *Engine) publish(events []EngineEvent) {
+	if len(events) == 0 {
+		return
+	}
+	e.subscribers.mu.Lock()
+	defer e.subscribers.mu.Unlock()
+	dropped := 0
+	for ch := range e.subscribers.subs {
+		for _, ev := range events {
+			select {
+			case ch <- ev:
+			default:
+				dropped++
+			}
+		}
+	}
+	if dropped > 0 {
+		log.Printf("scoring engine: dropped %d events for slow subscribers", dropped)
+	}
+}
+
+// newReleases lists the ingested indicators of next that are new or moved
+// since prev: a later release time, or a different value when release times
+// are unknown. Derived indicators are reported through the scores they feed.
+func newReleases(prev, next MacroSnapshot) []IndicatorRelease {
+	var out []IndicatorRelease
+	for _, def := range Indicators {
+		if def.Derived {
+			continue
+		}
+		v, ok := next.Indicator(def.Key)
+		if !ok {
+			continue
+		}
+		old, hadOld := prev.Indicator(def.Key)
+		at, hasAt := next.ReleasedAt[def.Key]
+		oldAt, hadOldAt := prev.ReleasedAt[def.Key]
+		switch {
+		case hasAt && hadOldAt:
+			if !at.After(oldAt) {
+				continue
+			}
+		case hadOld && old == v:
+			continue
+		}
+
+		rel := IndicatorRelease{Currency: next.Country, Indicator: def.Key, Label: def.Label, Value: v}
+		if hadOld {
+			rel.Previous = &old
+		}
+		if hasAt {
+			rel.ReleasedAt = &at
+		}
+		out = append(out, rel)
+	}
+	return out
+}
+
+// changeEvents wraps score changes as events, naming the currencies each
+// score depends on so subscribers can filter by currency.
+func changeEvents(changes []ScoreChange, instruments []InstrumentDef) []EngineEvent {
+	defs := make(map[string]InstrumentDef, len(instruments))
+	for _, inst := range instruments {
+		defs[inst.Symbol] = inst
+	}
+
+	events := make([]EngineEvent, 0, len(changes))
+	for _, c := range changes {
+		ev := EngineEvent{Type: c.Kind, TS: c.TS, Score: &c}
+		switch c.Kind {
+		case ChangeCurrency:
+			ev.Currencies = []string{c.Subject}
+		case ChangePair:
+			ev.Currencies = []string{c.Base, c.Quote}
+			ev.Symbol = c.Subject
+		case ChangeInstrument:
+			ev.Currencies = instrumentCurrencies(defs[c.Subject])
+			ev.Symbol = c.Subject
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+// instrumentCurrencies lists an instrument's FX legs and driver currencies,
+// leaving out the GLOBAL driver.
+func instrumentCurrencies(inst InstrumentDef) []string {
+	seen := make(map[string]bool)
+	add := func(code string) {
+		if code != "" && code != GlobalDriver {
+			seen[code] = true
+		}
+	}
+	add(inst.BaseFX)
+	add(inst.QuoteFX)
+	for _, d := range inst.Drivers {
+		add(d.Currency)
+	}
+	out := make([]string, 0, len(seen))
+	for code := range seen {
+		out = append(out, code)
+	}
+	sort.Strings(out)
+	return out
+}
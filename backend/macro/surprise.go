@@ -0,0 +1,215 @@
+package macro
+
+import (
+	"context"
+	"economic_indicator/models"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// SurpriseIndicator is the derived indicator key carrying the economic surprise
+// index; scoring models can use it like any ingested indicator.
+const SurpriseIndicator = "surprise_index"
+
+// Surprise index defaults: releases are weighted by a 30-day half-life over a 90-day window.
+const (
+	DefaultSurpriseHalfLife = 30 * 24 * time.Hour
+	DefaultSurpriseWindow   = 90 * 24 * time.Hour
+)
+
+// SurpriseOptions controls the surprise index calculation.
+type SurpriseOptions struct {
+	AsOf            time.Time     // zero means now
+	ReleaseTimeOnly bool          // see ScoreOptions.ReleaseTimeOnly
+	HalfLife        time.Duration // zero selects DefaultSurpriseHalfLife
+	Window          time.Duration // zero selects DefaultSurpriseWindow
+}
+
+// SurpriseRelease is one release's contribution to a surprise index.
+type SurpriseRelease struct {
+	Indicator string    `json:"indicator"`
+	DateTime  time.Time `json:"datetime"`
+	Actual    float64   `json:"actual"`
+	Forecast  float64   `json:"forecast"`
+	Surprise  float64   `json:"surprise"` // actual minus forecast
+	Z         float64   `json:"z"`        // surprise over the indicator's surprise std, signed by polarity
+	Weight    float64   `json:"weight"`   // time decay
+}
+
+// SurpriseIndex is a Citi-style economic surprise index for one currency: the
+// decay-weighted average of standardised actual-minus-consensus surprises.
+// Positive means data has been beating expectations.
+type SurpriseIndex struct {
+	Currency string            `json:"currency"`
+	Index    float64           `json:"index"`
+	AsOf     time.Time         `json:"as_of"`
+	Releases []SurpriseRelease `json:"releases"`
+}
+
+// surpriseObservation is one stored release that had a consensus forecast.
+type surpriseObservation struct {
+	Currency  string
+	Indicator string
+	DateTime  time.Time
+	Actual    float64
+	Forecast  float64
+}
+
+// Surprises computes the surprise index per currency from releases with a forecast.
+func (r *SnapshotRepository) Surprises(ctx context.Context, opts SurpriseOptions) (map[string]SurpriseIndex, error) {
+	if r.DB == nil {
+		return nil, fmt.Errorf("no database configured")
+	}
+
+	var rows []models.EconIndicator
+	err := r.DB.NewSelect().
+		Model(&rows).
+		Column("country", "category", "value", "forecast", "datetime").
+		Where("value IS NOT NULL").
+		Where("forecast IS NOT NULL").
+		Apply(asOfFilter(ScoreOptions{AsOf: opts.AsOf, ReleaseTimeOnly: opts.ReleaseTimeOnly})).
+		Order("datetime ASC", "revision ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("select forecasts: %w", err)
+	}
+
+	// the market is surprised by the first print, not by later revisions
+	obs := make([]surpriseObservation, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		key := row.Country + "|" + row.Category + "|" + row.DateTime.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		code, ok := CurrencyForCountry(row.Country)
+		if !ok {
+			continue
+		}
+		def, ok := LookupIndicator(row.Category)
+		if !ok || def.Derived {
+			continue
+		}
+		obs = append(obs, surpriseObservation{
+			Currency:  code,
+			Indicator: def.Key,
+			DateTime:  row.DateTime,
+			Actual:    *row.Value,
+			Forecast:  *row.Forecast,
+		})
+	}
+
+	return buildSurpriseIndex(obs, opts), nil
+}
+
+// buildSurpriseIndex standardises each surprise by the std of all surprises of
+// the same currency and indicator, then averages the releases inside the window
+// with exponential time decay. Target-polarity indicators (inflation) are left
+// out: a hot print is neither good nor bad news for activity.
+func buildSurpriseIndex(obs []surpriseObservation, opts SurpriseOptions) map[string]SurpriseIndex {
+	now := opts.AsOf
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	halfLife := opts.HalfLife
+	if halfLife <= 0 {
+		halfLife = DefaultSurpriseHalfLife
+	}
+	window := opts.Window
+	if window <= 0 {
+		window = DefaultSurpriseWindow
+	}
+
+	// surprise history per currency and indicator, for standardising
+	history := make(map[string]map[string][]float64)
+	for _, o := range obs {
+		if history[o.Currency] == nil {
+			history[o.Currency] = make(map[string][]float64)
+		}
+		history[o.Currency][o.Indicator] = append(history[o.Currency][o.Indicator], o.Actual-o.Forecast)
+	}
+
+	out := make(map[string]SurpriseIndex)
+	sums := make(map[string][2]float64) // weighted z sum, weight sum
+	for _, o := range obs {
+		def, ok := LookupIndicator(o.Indicator)
+		if !ok || def.Polarity == PolarityTarget {
+			continue
+		}
+		age := now.Sub(o.DateTime)
+		if age < 0 || age > window {
+			continue
+		}
+		std := stdDev(history[o.Currency][o.Indicator])
+		if std == 0 {
+			continue
+		}
+
+		surprise := o.Actual - o.Forecast
+		z := float64(def.Polarity) * surprise / std
+		weight := math.Pow(0.5, float64(age)/float64(halfLife))
+
+		idx := out[o.Currency]
+		idx.Currency = o.Currency
+		idx.AsOf = now
+		idx.Releases = append(idx.Releases, SurpriseRelease{
+			Indicator: o.Indicator,
+			DateTime:  o.DateTime,
+			Actual:    o.Actual,
+			Forecast:  o.Forecast,
+			Surprise:  round(surprise, 3),
+			Z:         round(z, 3),
+			Weight:    round(weight, 3),
+		})
+		out[o.Currency] = idx
+
+		s := sums[o.Currency]
+		s[0] += weight * z
+		s[1] += weight
+		sums[o.Currency] = s
+	}
+
+	for code, idx := range out {
+		if s := sums[code]; s[1] > 0 && s[0] != 0 {
+			idx.Index = round(s[0]/s[1], 3) // s[0] == 0 would round to -0 in JSON
+		}
+		sort.Slice(idx.Releases, func(i, j int) bool { return idx.Releases[i].DateTime.After(idx.Releases[j].DateTime) })
+		out[code] = idx
+	}
+	return out
+}
+
+// stdDev is the sample standard deviation; 0 with fewer than minHistory values.
+func stdDev(sample []float64) float64 {
+	if len(sample) < minHistory {
+		return 0
+	}
+	_, std := meanStd(sample)
+	return std
+}
+
+// applySurprises stores each currency's surprise index on its snapshot, dated by
+// the most recent release that fed it.
+func applySurprises(snapshots []MacroSnapshot, surprises map[string]SurpriseIndex) {
+	for i := range snapshots {
+		idx, ok := surprises[snapshots[i].Country]
+		if !ok || len(idx.Releases) == 0 {
+			continue
+		}
+		snapshots[i].setIndicator(SurpriseIndicator, idx.Index)
+		snapshots[i].setRelease(SurpriseIndicator, idx.Releases[0].DateTime)
+	}
+}
+
+// usesIndicator reports whether any of the model's rules reads key.
+func (m *ScoringModel) usesIndicator(key string) bool {
+	for _, rule := range m.Rules {
+		if rule.Indicator == key {
+			return true
+		}
+	}
+	return false
+}
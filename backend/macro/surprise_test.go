@@ -0,0 +1,69 @@
+package macro
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildSurpriseIndex(t *testing.T) {
+	now := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	at := func(days int) time.Time { return now.AddDate(0, 0, days) }
+	surprise := func(code, key string, days int, s float64) surpriseObservation {
+		return surpriseObservation{Currency: code, Indicator: key, DateTime: at(days), Actual: 50 + s, Forecast: 50}
+	}
+
+	obs := []surpriseObservation{
+		// EUR: surprises +1, -1, +1 (std 2/sqrt(3)); the oldest is out of the window but sets the std
+		surprise("EUR", "manufacturing_pmi", -120, 1),
+		surprise("EUR", "manufacturing_pmi", -30, -1),
+		surprise("EUR", "manufacturing_pmi", 0, 1),
+		// GBP: higher unemployment than expected is bad news
+		surprise("GBP", "unemployment_rate", -60, 0.1),
+		surprise("GBP", "unemployment_rate", -30, -0.1),
+		surprise("GBP", "unemployment_rate", 0, 0.1),
+		// USD: inflation has target polarity and is left out
+		surprise("USD", "inflation_rate", -20, 0.3),
+		surprise("USD", "inflation_rate", -10, -0.1),
+		surprise("USD", "inflation_rate", 0, 0.2),
+		// JPY: only old and future releases
+		surprise("JPY", "manufacturing_pmi", -200, 1),
+		surprise("JPY", "manufacturing_pmi", -150, -1),
+		surprise("JPY", "manufacturing_pmi", 10, 1),
+		// AUD: too few releases to standardise
+		surprise("AUD", "manufacturing_pmi", -10, 1),
+		surprise("AUD", "manufacturing_pmi", 0, 2),
+	}
+	got := buildSurpriseIndex(obs, SurpriseOptions{AsOf: now, HalfLife: 30 * day, Window: 90 * day})
+
+	z := math.Sqrt(3) / 2 // |surprise| / std for both EUR and GBP
+	want := map[string]struct {
+		index    float64
+		releases int
+	}{
+		"EUR": {(1*z - 0.5*z) / 1.5, 2},
+		"GBP": {(-0.25*z + 0.5*z - 1*z) / 1.75, 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("indices for %d currencies, want EUR and GBP: %+v", len(got), got)
+	}
+	for code, w := range want {
+		idx := got[code]
+		if math.Abs(idx.Index-w.index) > 1e-3 || len(idx.Releases) != w.releases {
+			t.Errorf("%s index = %v from %d releases, want %.3f from %d", code, idx.Index, len(idx.Releases), w.index, w.releases)
+		}
+		if !idx.AsOf.Equal(now) {
+			t.Errorf("%s as of %v", code, idx.AsOf)
+		}
+		for i := 1; i < len(idx.Releases); i++ {
+			if idx.Releases[i].DateTime.After(idx.Releases[i-1].DateTime) {
+				t.Errorf("%s releases not newest first", code)
+			}
+		}
+	}
+
+	latest := got["GBP"].Releases[0]
+	if latest.Weight != 1 || math.Abs(latest.Z+z) > 1e-3 || math.Abs(latest.Surprise-0.1) > 1e-9 {
+		t.Errorf("latest GBP release = %+v, want weight 1 and z %.3f", latest, -z)
+	}
+}
@@ -1,19 +1,52 @@
 package main
 
 import (
+	"context"
 	"economic_indicator/api"
 	"economic_indicator/config"
 	"economic_indicator/db"
+	"economic_indicator/macro"
 	"log"
 	"net/http"
+	"time"
 )
 
+// engineStartAttempts bounds how long startup waits for the database before
+// serving without the scoring engine; the delay doubles from one second.
+const engineStartAttempts = 5
+
 func main() {
 	cfg := config.Load()
 
 	bunDB := db.Open(cfg.DBDSN)
 
-	apiServer := api.New(bunDB)
+	scoringModels, err := macro.LoadScoringModels(cfg.ScoringModelsPath)
+	if err != nil {
+		log.Fatalf("scoring models: %v", err)
+	}
+
+	apiServer := api.New(bunDB, scoringModels)
+
+	if cfg.EnginePollInterval > 0 {
+		model, err := scoringModels.Get(macro.DefaultModelName)
+		if err != nil {
+			log.Fatalf("scoring engine: %v", err)
+		}
+		engine := macro.NewEngine(apiServer.Snapshots, apiServer.Instruments, macro.ScoreOptions{Model: model})
+		engine.PollInterval = cfg.EnginePollInterval
+		if err := startEngine(context.Background(), engine); err != nil {
+			// REST scores fall back to on-demand scoring; streams answer 503
+			log.Printf("scoring engine disabled: %v", err)
+		} else {
+			apiServer.Engine = engine
+			go func() {
+				if err := engine.Run(context.Background()); err != nil {
+					log.Printf("scoring engine stopped: %v", err)
+				}
+			}()
+		}
+	}
+
 	router := apiServer.Router()
 
 	log.Printf("backend listening on %s", cfg.Addr)
@@ -21,3 +54,18 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// startEngine runs the engine's first scoring pass, retrying with backoff so a
+// database that is briefly down at boot doesn't disable live rescoring.
+func startEngine(ctx context.Context, engine *macro.Engine) error {
+	delay := time.Second
+	for attempt := 1; ; attempt++ {
+		err := engine.Start(ctx)
+		if err == nil || attempt == engineStartAttempts {
+			return err
+		}
+		log.Printf("scoring engine: start attempt %d failed, retrying in %s: %v", attempt, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
@@ -20,45 +20,128 @@ type Currency struct {
 type CurrencyScore struct {
 	bun.BaseModel `bun:"table:currency_scores"`
 
-	ID         int64     `bun:",pk,autoincrement"`
-	CurrencyID int64     `bun:",notnull"`
-	TS         time.Time `bun:",notnull"`
-	EconScore  float64   `bun:",nullzero"`
-	CreatedAt  time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+	ID          int64              `bun:",pk,autoincrement"`
+	CurrencyID  int64              `bun:",notnull"`
+	TS          time.Time          `bun:",notnull"`
+	EconScore   float64            `bun:",nullzero"`
+	Model       string             `bun:",notnull,default:'default'"` // scoring model name
+	Components  map[string]float64 `bun:"components,type:json"`
+	Explanation string             `bun:",type:text,nullzero"`
+	CreatedAt   time.Time          `bun:",nullzero,notnull,default:current_timestamp"`
 }
 
 // Instrument Instrument
 type Instrument struct {
 	bun.BaseModel `bun:"table:instruments"`
 
-	ID        int64     `bun:",pk,autoincrement"`
-	Symbol    string    `bun:",unique,notnull"`
-	Name      string    `bun:",nullzero"`
-	AssetType string    `bun:",nullzero"` // "index", "metal", "fx"
-	CreatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+	ID             int64              `bun:",pk,autoincrement"`
+	Symbol         string             `bun:",unique,notnull"`
+	Name           string             `bun:",nullzero"`
+	AssetType      string             `bun:",nullzero"`  // "index", "metal", "fx"
+	DriverCurrency string             `bun:",nullzero"`  // currency whose macro score drives the instrument (fx: base)
+	QuoteCurrency  string             `bun:",nullzero"`  // fx only: quote currency
+	Drivers        []InstrumentDriver `bun:",type:json"` // further weighted currency drivers
+	CreatedAt      time.Time          `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
+// InstrumentDriver is one weighted currency input stored on an instrument
+type InstrumentDriver struct {
+	Currency  string  `json:"currency"`            // currency code or "GLOBAL"
+	Component string  `json:"component,omitempty"` // score component; empty means the total score
+	Weight    float64 `json:"weight"`              // signed weight
 }
 
 // InstrumentScore InstrumentScore
 type InstrumentScore struct {
 	bun.BaseModel `bun:"table:instrument_scores"`
 
-	ID           int64     `bun:",pk,autoincrement"`
-	InstrumentID int64     `bun:",notnull"`
-	TS           time.Time `bun:",notnull"`
-	FinalScore   float64   `bun:",nullzero"`
-	CreatedAt    time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+	ID           int64              `bun:",pk,autoincrement"`
+	InstrumentID int64              `bun:",notnull"`
+	TS           time.Time          `bun:",notnull"`
+	FinalScore   float64            `bun:",nullzero"`
+	Model        string             `bun:",notnull,default:'default'"` // scoring model name
+	Components   map[string]float64 `bun:"components,type:json"`
+	Explanation  string             `bun:",type:text,nullzero"`
+	CreatedAt    time.Time          `bun:",nullzero,notnull,default:current_timestamp"`
 }
 
-// EconIndicator table
+// EconIndicator table, one row per release of a (country, category) indicator
+// and revision of it; every revision keeps the time it was ingested
 type EconIndicator struct {
 	bun.BaseModel `bun:"table:econ_indicators"`
 
 	ID         int64     `bun:",pk,autoincrement"`
-	Country    string    `bun:"country"`
-	Category   string    `bun:"category"`
+	Country    string    `bun:"country,unique:country_category_datetime_revision"`
+	Category   string    `bun:"category,unique:country_category_datetime_revision"`
 	Value      *float64  `bun:"value"`
 	Previous   *float64  `bun:"previous"`
-	DateTime   time.Time `bun:"datetime"`
+	Forecast   *float64  `bun:"forecast"` // consensus expectation before the release
+	DateTime   time.Time `bun:"datetime,unique:country_category_datetime_revision"`
+	Revision   int       `bun:"revision,notnull,default:0,unique:country_category_datetime_revision"` // 0 for the first print
+	Raw        []byte    `bun:"raw"`
+	Source     string    `bun:"source,nullzero"` // ingest provider that stored the release
+	IngestedAt time.Time `bun:"ingested_at,notnull,default:current_timestamp"`
+}
+
+// PolicyDecision table, one row per stored revision of a central-bank rate decision
+type PolicyDecision struct {
+	bun.BaseModel `bun:"table:policy_decisions"`
+
+	ID          int64      `bun:",pk,autoincrement"`
+	Currency    string     `bun:"currency,unique:currency_datetime_revision"`
+	DateTime    time.Time  `bun:"datetime,unique:currency_datetime_revision"`                   // announcement time
+	Revision    int        `bun:"revision,notnull,default:0,unique:currency_datetime_revision"` // 0 as first stored
+	Rate        float64    `bun:"rate"`                                                         // policy rate after the decision, %
+	VotesHike   int        `bun:"votes_hike"`                                                   // vote split; all zero when not published
+	VotesHold   int        `bun:"votes_hold"`
+	VotesCut    int        `bun:"votes_cut"`
+	Guidance    string     `bun:"guidance,nullzero"` // hawkish, dovish or neutral
+	NextMeeting *time.Time `bun:"next_meeting"`      // next scheduled decision, when announced
+	Source      string     `bun:"source,nullzero"`   // ingest provider that stored the decision
+	IngestedAt  time.Time  `bun:"ingested_at,notnull,default:current_timestamp"`
+}
+
+// CalendarEvent table, one row per scheduled release on the economic calendar
+type CalendarEvent struct {
+	bun.BaseModel `bun:"table:calendar_events"`
+
+	ID         int64     `bun:",pk,autoincrement"`
+	Country    string    `bun:"country,unique:country_event_datetime"`
+	Event      string    `bun:"event,unique:country_event_datetime"` // provider event name, e.g. "Non Farm Payrolls"
+	DateTime   time.Time `bun:"datetime,unique:country_event_datetime"`
+	Category   string    `bun:"category,nullzero"` // canonical category when the registry knows it
+	Importance int       `bun:"importance"`        // 1 low, 2 medium, 3 high
+	Forecast   *float64  `bun:"forecast"`
+	Previous   *float64  `bun:"previous"`
 	Raw        []byte    `bun:"raw"`
+	Source     string    `bun:"source,nullzero"` // ingest provider that stored the event
 	IngestedAt time.Time `bun:"ingested_at,notnull,default:current_timestamp"`
 }
+
+// IngestNotice table, written by ingest after it stores new data for a currency
+// so that a running scoring engine knows what to rescore
+type IngestNotice struct {
+	bun.BaseModel `bun:"table:ingest_notices"`
+
+	ID        int64     `bun:",pk,autoincrement"`
+	Currency  string    `bun:"currency,notnull"`
+	Kind      string    `bun:"kind,notnull"` // "indicators" or "policy"
+	Stored    int       `bun:"stored"`       // rows stored by the ingest run
+	CreatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
+// ScoreChangeEvent table, one row per score the scoring engine saw move
+type ScoreChangeEvent struct {
+	bun.BaseModel `bun:"table:score_change_events"`
+
+	ID          int64     `bun:",pk,autoincrement"`
+	TS          time.Time `bun:",notnull"`
+	Kind        string    `bun:"kind,notnull"`    // "currency", "instrument" or "pair"
+	Subject     string    `bun:"subject,notnull"` // currency code, instrument symbol or pair, e.g. EURUSD
+	Model       string    `bun:",notnull"`        // scoring model name
+	Before      *float64  `bun:"score_before"`    // nil for a first score
+	After       float64   `bun:"score_after"`
+	Delta       float64   `bun:"delta"`
+	TriggeredBy string    `bun:"triggered_by"` // currencies whose new data caused the rescore, e.g. "EUR,USD"
+	CreatedAt   time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+}
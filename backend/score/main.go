@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"economic_indicator/config"
+	"economic_indicator/db"
+	"economic_indicator/macro"
+	"flag"
+	"log"
+	"time"
+)
+
+const macroFilePath = "data/macro.json"
+
+func main() {
+	asOf := flag.String("as-of", "", "score as of this RFC3339 time or YYYY-MM-DD date (default now)")
+	releaseTimeOnly := flag.Bool("release-time-only", false, "ignore ingested_at when applying -as-of (backfills)")
+	modelName := flag.String("model", macro.DefaultModelName, "scoring model name")
+	minCoverage := flag.Float64("min-coverage", macro.DefaultMinCoverage, "data coverage below which scores are flagged low-confidence")
+	decayHalfLife := flag.Duration("decay-half-life", 0, "halve component weights per this age of their release, e.g. 2160h (default off)")
+	flag.Parse()
+
+	opts := macro.ScoreOptions{MinCoverage: *minCoverage, DecayHalfLife: *decayHalfLife}
+	ts := time.Now().UTC()
+	if *asOf != "" {
+		t, err := parseTime(*asOf)
+		if err != nil {
+			log.Fatalf("invalid -as-of: %v", err)
+		}
+		opts.AsOf = t
+		opts.ReleaseTimeOnly = *releaseTimeOnly
+		ts = t
+	}
+
+	cfg := config.Load()
+
+	scoringModels, err := macro.LoadScoringModels(cfg.ScoringModelsPath)
+	if err != nil {
+		log.Fatalf("scoring models: %v", err)
+	}
+	opts.Model, err = scoringModels.Get(*modelName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bunDB := db.Open(cfg.DBDSN)
+
+	ctx := context.Background()
+
+	repo := macro.NewSnapshotRepository(bunDB, macroFilePath)
+	currencyScores, err := repo.Scores(ctx, opts)
+	if err != nil {
+		log.Fatalf("score snapshots: %v", err)
+	}
+	instruments, err := macro.NewInstrumentRepository(bunDB).List(ctx)
+	if err != nil {
+		log.Fatalf("load instruments: %v", err)
+	}
+	instrumentScores := macro.BuildInstrumentScores(currencyScores, instruments)
+
+	if err := macro.SaveScores(ctx, bunDB, ts, opts.Model.Name, currencyScores, instrumentScores); err != nil {
+		log.Fatalf("save scores: %v", err)
+	}
+
+	log.Printf("✅ Stored %d currency and %d instrument scores (model %s) at %s",
+		len(currencyScores), len(instrumentScores), opts.Model.Name, ts.Format(time.RFC3339))
+}
+
+func parseTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", v)
+}
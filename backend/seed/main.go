@@ -61,10 +61,33 @@ func seedCurrencies(ctx context.Context, database *bun.DB) error {
 
 func seedInstruments(ctx context.Context, database *bun.DB) error {
 	instruments := []models.Instrument{
-		{Symbol: "US500", Name: "S&P 500", AssetType: "index"},
-		{Symbol: "US100", Name: "Nasdaq 100", AssetType: "index"},
-		{Symbol: "XAUUSD", Name: "Gold", AssetType: "metal"},
-		{Symbol: "XAGUSD", Name: "Silver", AssetType: "metal"},
+		{Symbol: "US500", Name: "S&P 500", AssetType: "index", DriverCurrency: "USD"},
+		{Symbol: "US100", Name: "Nasdaq 100", AssetType: "index", DriverCurrency: "USD"},
+		{Symbol: "JP225", Name: "Nikkei 225", AssetType: "index", DriverCurrency: "JPY", Drivers: []models.InstrumentDriver{
+			{Currency: "USD", Weight: 0.5},
+			{Currency: "JPY", Weight: -0.5},
+		}},
+		{Symbol: "XAUUSD", Name: "Gold", AssetType: "metal", DriverCurrency: "USD", Drivers: []models.InstrumentDriver{
+			{Currency: "GLOBAL", Component: "inflation", Weight: -0.3},
+		}},
+		{Symbol: "XAGUSD", Name: "Silver", AssetType: "metal", DriverCurrency: "USD", Drivers: []models.InstrumentDriver{
+			{Currency: "GLOBAL", Component: "manufacturing_pmi", Weight: 0.3},
+		}},
+		{Symbol: "USOIL", Name: "WTI Crude Oil", AssetType: "energy", DriverCurrency: "USD", Drivers: []models.InstrumentDriver{
+			{Currency: "GLOBAL", Component: "manufacturing_pmi", Weight: 0.3},
+		}},
+		{Symbol: "UKOIL", Name: "Brent Crude Oil", AssetType: "energy", DriverCurrency: "USD", Drivers: []models.InstrumentDriver{
+			{Currency: "GLOBAL", Component: "manufacturing_pmi", Weight: 0.5},
+		}},
+		{Symbol: "US10Y", Name: "US 10Y Treasury Note", AssetType: "bond", DriverCurrency: "USD"},
+		{Symbol: "DE10Y", Name: "German 10Y Bund", AssetType: "bond", DriverCurrency: "EUR"},
+		{Symbol: "BTCUSD", Name: "Bitcoin / US Dollar", AssetType: "crypto", DriverCurrency: "USD"},
+		{Symbol: "EURUSD", Name: "Euro / US Dollar", AssetType: "fx", DriverCurrency: "EUR", QuoteCurrency: "USD"},
+		{Symbol: "GBPUSD", Name: "British Pound / US Dollar", AssetType: "fx", DriverCurrency: "GBP", QuoteCurrency: "USD"},
+		{Symbol: "USDJPY", Name: "US Dollar / Japanese Yen", AssetType: "fx", DriverCurrency: "USD", QuoteCurrency: "JPY"},
+		{Symbol: "AUDUSD", Name: "Australian Dollar / US Dollar", AssetType: "fx", DriverCurrency: "AUD", QuoteCurrency: "USD"},
+		{Symbol: "USDCHF", Name: "US Dollar / Swiss Franc", AssetType: "fx", DriverCurrency: "USD", QuoteCurrency: "CHF"},
+		{Symbol: "GBPJPY", Name: "British Pound / Japanese Yen", AssetType: "fx", DriverCurrency: "GBP", QuoteCurrency: "JPY"},
 	}
 
 	for _, inst := range instruments {
@@ -75,6 +98,17 @@ func seedInstruments(ctx context.Context, database *bun.DB) error {
 			Scan(ctx)
 
 		if err == nil {
+			// rows seeded before instruments had drivers are not scored until they get one
+			if existing.DriverCurrency == "" {
+				existing.DriverCurrency = inst.DriverCurrency
+				existing.QuoteCurrency = inst.QuoteCurrency
+				existing.Drivers = inst.Drivers
+				if _, err := database.NewUpdate().Model(&existing).WherePK().Exec(ctx); err != nil {
+					return err
+				}
+				log.Printf("Set drivers on instrument %s", inst.Symbol)
+				continue
+			}
 			log.Printf("Instrument %s already exists, skipping", inst.Symbol)
 			continue
 		}